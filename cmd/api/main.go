@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"time"
+	_ "time/tzdata"
 
 	ratelimit "github.com/JGLTechnologies/gin-rate-limit"
 
@@ -49,19 +50,56 @@ func main() {
 	})
 
 	userRepo := repo.NewUserRepo(db.DB)
-	userService := services.NewUserService(userRepo)
+	exerciseRepo := repo.NewExerciseRepo(db.DB)
+
+	recordRepo := repo.NewRecordRepo(db.DB)
+	recordService := services.NewRecordService(recordRepo, exerciseRepo, userRepo)
+	recordHandler := handlers.NewRecordHandler(recordService)
+
+	historyRepo := repo.NewHistoryRepo(db.DB)
+	historyService := services.NewHistoryService(historyRepo, recordService)
+	historyHandler := handlers.NewHistoryHandler(historyService)
+
+	analyticsRepo := repo.NewAnalyticsRepo(db.DB)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, userRepo)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+
+	bodyweightRepo := repo.NewBodyweightRepo(db.DB)
+	bodyweightService := services.NewBodyweightService(bodyweightRepo, userRepo, recordService)
+	bodyweightHandler := handlers.NewBodyweightHandler(bodyweightService)
+
+	userService := services.NewUserService(userRepo, recordService)
 	userHandler := handlers.NewUserHandler(userService)
 
-	exerciseRepo := repo.NewExerciseRepo(db.DB)
-	exerciseService := services.NewExerciseService(exerciseRepo)
+	exerciseService := services.NewExerciseService(exerciseRepo, userRepo, recordService)
 	exerciseHandler := handlers.NewExerciseHandler(exerciseService)
 
+	categoryRepo := repo.NewCategoryRepo(db.DB)
+	categoryService := services.NewCategoryService(categoryRepo)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+
+	muscleGroupRepo := repo.NewMuscleGroupRepo(db.DB)
+	muscleGroupService := services.NewMuscleGroupService(muscleGroupRepo)
+	muscleGroupHandler := handlers.NewMuscleGroupHandler(muscleGroupService)
+
 	workoutRepo := repo.NewWorkoutRepo(db.DB)
 	workoutExerciseRepo := repo.NewWorkoutExerciseRepo(db.DB)
+	workoutGroupRepo := repo.NewWorkoutGroupRepo(db.DB)
 	setRepo := repo.NewSetRepo(db.DB)
-	workoutService := services.NewWorkoutService(workoutRepo, workoutExerciseRepo, setRepo)
+	restTimerRepo := repo.NewRestTimerRepo(db.DB)
+	restTimerService := services.NewRestTimerService(restTimerRepo, exerciseRepo)
+	if err := restTimerService.Restore(); err != nil {
+		log.Printf("warning: failed to restore rest timers: %v", err)
+	}
+	restTimerHandler := handlers.NewRestTimerHandler(restTimerService)
+
+	workoutService := services.NewWorkoutService(workoutRepo, workoutExerciseRepo, workoutGroupRepo, setRepo, exerciseRepo, recordService, restTimerService)
 	workoutHandler := handlers.NewWorkoutHandler(workoutService)
 
+	routineRepo := repo.NewRoutineRepo(db.DB)
+	routineService := services.NewRoutineService(routineRepo, exerciseRepo, workoutService, recordService)
+	routineHandler := handlers.NewRoutineHandler(routineService)
+
 	api := router.Group("/api")
 	api.Use(rateLimiter)
 	{
@@ -79,21 +117,81 @@ func main() {
 			// workouts
 			authorized.POST("/workouts", workoutHandler.CreateWorkout)
 			authorized.GET("/workouts", workoutHandler.ListWorkouts)
+			authorized.POST("/workouts/start", workoutHandler.StartWorkout)
+			authorized.GET("/workouts/active", workoutHandler.GetActiveWorkout)
+			authorized.POST("/workouts/:id/finish", workoutHandler.FinishWorkout)
+			authorized.GET("/workouts/active/rest", restTimerHandler.GetRestTimer)
+			authorized.DELETE("/workouts/active/rest", restTimerHandler.SkipRest)
+			authorized.POST("/workouts/:id/duplicate", workoutHandler.DuplicateWorkout)
 			authorized.GET("/workouts/:id", workoutHandler.GetWorkout)
 			authorized.GET("/workouts/:id/details", workoutHandler.GetWorkoutDetails)
 			authorized.PUT("/workouts/:id", workoutHandler.UpdateWorkout)
+			authorized.PUT("/workouts/:id/details", workoutHandler.ReplaceWorkoutDetails)
 			authorized.DELETE("/workouts/:id", workoutHandler.DeleteWorkout)
 			// misc
 			authorized.GET("/workouts/:id/report", workoutHandler.GetWorkoutReport)
 			authorized.GET("/exercises", exerciseHandler.ListAllExercises)
+			authorized.POST("/exercises", exerciseHandler.CreateExercise)
+			authorized.PUT("/exercises/:id", exerciseHandler.UpdateExercise)
+			authorized.DELETE("/exercises/:id", exerciseHandler.DeleteExercise)
+			authorized.PUT("/exercises/:id/muscle-groups", exerciseHandler.SetSecondaryMuscleGroups)
+			authorized.PUT("/exercises/:id/rest-default", restTimerHandler.SetRestDefault)
+			authorized.DELETE("/exercises/:id/rest-default", restTimerHandler.DeleteRestDefault)
+			// records
+			authorized.GET("/records", recordHandler.ListRecords)
+			authorized.GET("/exercises/:id/records", recordHandler.ListExerciseRecords)
+			authorized.GET("/exercises/:id/e1rm", recordHandler.GetE1RMHistory)
+			authorized.GET("/exercises/:id/history", historyHandler.GetExerciseHistory)
+
+			authorized.GET("/analytics/volume", analyticsHandler.GetVolume)
+			authorized.GET("/analytics/calendar", analyticsHandler.GetCalendar)
+
+			authorized.GET("/me/preferences", userHandler.GetPreferences)
+			authorized.PUT("/me/preferences", userHandler.UpdatePreferences)
+			authorized.POST("/me/bodyweight", bodyweightHandler.LogBodyweight)
+			authorized.GET("/me/bodyweight", bodyweightHandler.ListBodyweights)
+			authorized.DELETE("/me/bodyweight/:id", bodyweightHandler.DeleteBodyweight)
+			authorized.GET("/me/rest-defaults", restTimerHandler.ListRestDefaults)
+			authorized.GET("/me/events", restTimerHandler.StreamEvents)
+			// categories & muscle groups
+			authorized.GET("/categories", categoryHandler.ListCategories)
+			authorized.GET("/categories/:id", categoryHandler.GetCategory)
+			authorized.GET("/muscle-groups", muscleGroupHandler.ListMuscleGroups)
+			authorized.GET("/muscle-groups/:id", muscleGroupHandler.GetMuscleGroup)
 			// exercises
 			authorized.POST("/workouts/:id/exercises", workoutHandler.AddExerciseToWorkout)
+			authorized.POST("/workouts/:id/exercises/reorder", workoutHandler.ReorderWorkoutExercises)
 			authorized.PUT("/workout-exercises/:id", workoutHandler.UpdateWorkoutExercise)
 			authorized.DELETE("/workout-exercises/:id", workoutHandler.DeleteWorkoutExercise)
+			// exercise groups
+			authorized.POST("/workouts/:id/groups", workoutHandler.CreateWorkoutGroup)
+			authorized.PUT("/workout-groups/:id", workoutHandler.UpdateWorkoutGroup)
+			authorized.DELETE("/workout-groups/:id", workoutHandler.DeleteWorkoutGroup)
 			//sets
 			authorized.POST("/workout-exercises/:id/sets", workoutHandler.AddSet)
 			authorized.PUT("/sets/:id", workoutHandler.UpdateSet)
 			authorized.DELETE("/sets/:id", workoutHandler.DeleteSet)
+			authorized.POST("/sets/:id/complete", workoutHandler.CompleteSet)
+			authorized.DELETE("/sets/:id/complete", workoutHandler.CompleteSet)
+			// routines
+			authorized.POST("/routines", routineHandler.CreateRoutine)
+			authorized.GET("/routines", routineHandler.ListRoutines)
+			authorized.GET("/routines/:id", routineHandler.GetRoutine)
+			authorized.PUT("/routines/:id", routineHandler.UpdateRoutine)
+			authorized.DELETE("/routines/:id", routineHandler.DeleteRoutine)
+			authorized.POST("/routines/:id/start", routineHandler.StartRoutine)
+			authorized.POST("/routines/:id/repeat", workoutHandler.RepeatRoutine)
+		}
+
+		admin := authorized.Group("/")
+		admin.Use(middleware.RequireAdmin(userService))
+		{
+			admin.POST("/categories", categoryHandler.CreateCategory)
+			admin.PUT("/categories/:id", categoryHandler.RenameCategory)
+			admin.DELETE("/categories/:id", categoryHandler.DeleteCategory)
+			admin.POST("/muscle-groups", muscleGroupHandler.CreateMuscleGroup)
+			admin.PUT("/muscle-groups/:id", muscleGroupHandler.RenameMuscleGroup)
+			admin.DELETE("/muscle-groups/:id", muscleGroupHandler.DeleteMuscleGroup)
 		}
 	}
 
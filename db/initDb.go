@@ -14,7 +14,9 @@ func InitDB() {
 	log.Println("db opening sqlite database...")
 	var err error
 
-	DB, err = sql.Open("sqlite3", "./db/app.db")
+	// the DSN option applies to every pooled connection; the PRAGMA below
+	// only reaches the one it runs on
+	DB, err = sql.Open("sqlite3", "./db/app.db?_foreign_keys=on")
 	if err != nil {
 		log.Fatal("db open error:", err)
 	}
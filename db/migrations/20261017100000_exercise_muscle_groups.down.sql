@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_exercise_muscle_groups_muscle_group;
+DROP TABLE IF EXISTS exercise_muscle_groups;
+ALTER TABLE users DROP COLUMN is_admin;
@@ -0,0 +1,15 @@
+PRAGMA foreign_keys = ON;
+
+ALTER TABLE users ADD COLUMN is_admin INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS exercise_muscle_groups (
+  exercise_id INTEGER NOT NULL,
+  muscle_group_id INTEGER NOT NULL,
+
+  PRIMARY KEY (exercise_id, muscle_group_id),
+  FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+  FOREIGN KEY (muscle_group_id) REFERENCES muscle_groups(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_exercise_muscle_groups_muscle_group
+  ON exercise_muscle_groups(muscle_group_id);
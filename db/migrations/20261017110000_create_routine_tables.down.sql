@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_workouts_routine;
+ALTER TABLE workouts DROP COLUMN routine_id;
+DROP TABLE IF EXISTS routine_sets;
+DROP TABLE IF EXISTS routine_exercises;
+DROP TABLE IF EXISTS routines;
@@ -0,0 +1,52 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS routines (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+
+  name TEXT NOT NULL,
+  notes TEXT,
+
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  updated_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS routine_exercises (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  routine_id INTEGER NOT NULL,
+  exercise_id INTEGER NOT NULL,
+  exercise_order INTEGER NOT NULL,
+  notes TEXT,
+
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+  FOREIGN KEY (exercise_id) REFERENCES exercises(id)
+);
+
+CREATE TABLE IF NOT EXISTS routine_sets (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  routine_exercise_id INTEGER NOT NULL,
+  set_number INTEGER NOT NULL,
+
+  reps INTEGER,
+  weight REAL,
+
+  FOREIGN KEY (routine_exercise_id) REFERENCES routine_exercises(id) ON DELETE CASCADE,
+  UNIQUE (routine_exercise_id, set_number)
+);
+
+ALTER TABLE workouts ADD COLUMN routine_id INTEGER REFERENCES routines(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_routines_user
+  ON routines(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_routine
+  ON routine_exercises(routine_id);
+
+CREATE INDEX IF NOT EXISTS idx_routine_sets_routine_exercise
+  ON routine_sets(routine_exercise_id);
+
+CREATE INDEX IF NOT EXISTS idx_workouts_routine
+  ON workouts(routine_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_personal_records_workout;
+DROP INDEX IF EXISTS idx_personal_records_user_exercise;
+DROP TABLE IF EXISTS personal_records;
@@ -0,0 +1,28 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS personal_records (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  exercise_id INTEGER NOT NULL,
+
+  record_type TEXT NOT NULL,
+  value REAL NOT NULL,
+
+  reps INTEGER,
+  weight REAL,
+
+  set_id INTEGER,
+  workout_id INTEGER NOT NULL,
+  achieved_at TEXT NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+  FOREIGN KEY (set_id) REFERENCES sets(id) ON DELETE CASCADE,
+  FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_exercise
+  ON personal_records(user_id, exercise_id);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_workout
+  ON personal_records(workout_id);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN e1rm_formula TEXT NOT NULL DEFAULT 'epley';
@@ -0,0 +1,3 @@
+-- performed_at values stay normalized to UTC
+ALTER TABLE workouts DROP COLUMN timezone;
+ALTER TABLE workouts DROP COLUMN utc_offset;
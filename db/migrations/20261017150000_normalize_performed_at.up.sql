@@ -0,0 +1,20 @@
+ALTER TABLE workouts ADD COLUMN utc_offset TEXT NOT NULL DEFAULT '+00:00';
+ALTER TABLE workouts ADD COLUMN timezone TEXT;
+
+-- SQLite's date functions understand offsets, 'T' or space separators,
+-- fractional seconds and bare dates. Rows that do not start with a date
+-- (bare numbers would be read as Julian days) are left as is.
+UPDATE workouts
+SET
+  utc_offset = CASE
+    WHEN performed_at GLOB '*[+-][0-9][0-9]:[0-9][0-9]' THEN substr(performed_at, -6)
+    ELSE '+00:00'
+  END,
+  performed_at = strftime('%Y-%m-%dT%H:%M:%SZ', performed_at)
+WHERE performed_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*'
+  AND strftime('%Y-%m-%dT%H:%M:%SZ', performed_at) IS NOT NULL;
+
+UPDATE personal_records
+SET achieved_at = strftime('%Y-%m-%dT%H:%M:%SZ', achieved_at)
+WHERE achieved_at GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]*'
+  AND strftime('%Y-%m-%dT%H:%M:%SZ', achieved_at) IS NOT NULL;
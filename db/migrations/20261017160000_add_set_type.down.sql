@@ -0,0 +1 @@
+ALTER TABLE sets DROP COLUMN set_type;
@@ -0,0 +1,2 @@
+ALTER TABLE sets ADD COLUMN set_type TEXT NOT NULL DEFAULT 'normal'
+  CHECK (set_type IN ('warmup', 'normal', 'drop', 'failure', 'amrap', 'backoff'));
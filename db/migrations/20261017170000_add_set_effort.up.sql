@@ -0,0 +1,4 @@
+ALTER TABLE sets ADD COLUMN rpe REAL CHECK (rpe IS NULL OR (rpe >= 6 AND rpe <= 10));
+ALTER TABLE sets ADD COLUMN rir INTEGER CHECK (rir IS NULL OR rir >= 0);
+ALTER TABLE sets ADD COLUMN tempo TEXT;
+ALTER TABLE sets ADD COLUMN rest_seconds INTEGER CHECK (rest_seconds IS NULL OR rest_seconds >= 0);
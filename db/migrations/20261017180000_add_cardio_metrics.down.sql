@@ -0,0 +1,7 @@
+ALTER TABLE sets DROP COLUMN max_heart_rate;
+ALTER TABLE sets DROP COLUMN avg_heart_rate;
+ALTER TABLE sets DROP COLUMN calories;
+ALTER TABLE sets DROP COLUMN distance_meters;
+ALTER TABLE sets DROP COLUMN duration_seconds;
+
+ALTER TABLE exercises DROP COLUMN metric_type;
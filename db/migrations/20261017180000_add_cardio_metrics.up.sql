@@ -0,0 +1,27 @@
+ALTER TABLE exercises ADD COLUMN metric_type TEXT NOT NULL DEFAULT 'weight_reps'
+  CHECK (metric_type IN ('weight_reps', 'reps', 'time', 'distance_time'));
+
+ALTER TABLE sets ADD COLUMN duration_seconds INTEGER CHECK (duration_seconds IS NULL OR duration_seconds >= 0);
+ALTER TABLE sets ADD COLUMN distance_meters REAL CHECK (distance_meters IS NULL OR distance_meters >= 0);
+ALTER TABLE sets ADD COLUMN calories INTEGER CHECK (calories IS NULL OR calories >= 0);
+ALTER TABLE sets ADD COLUMN avg_heart_rate INTEGER;
+ALTER TABLE sets ADD COLUMN max_heart_rate INTEGER;
+
+-- Global cardio exercises and the plank switch to time based metrics unless
+-- reps or weight were already logged for them.
+UPDATE exercises
+SET metric_type = CASE WHEN name = 'Plank' THEN 'time' ELSE 'distance_time' END
+WHERE owner_user_id IS NULL
+  AND (name = 'Plank' OR category_id IN (SELECT id FROM categories WHERE name = 'cardio'))
+  AND NOT EXISTS (
+    SELECT 1
+    FROM workout_exercises we
+    JOIN sets s ON s.workout_exercise_id = we.id
+    WHERE we.exercise_id = exercises.id AND (s.reps IS NOT NULL OR s.weight IS NOT NULL)
+  )
+  AND NOT EXISTS (
+    SELECT 1
+    FROM routine_exercises re
+    JOIN routine_sets rs ON rs.routine_exercise_id = re.id
+    WHERE re.exercise_id = exercises.id AND (rs.reps IS NOT NULL OR rs.weight IS NOT NULL)
+  );
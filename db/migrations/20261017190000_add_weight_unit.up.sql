@@ -0,0 +1,6 @@
+-- Weights have always been stored without a unit and every example used
+-- kilograms, so existing sets and routine sets are taken to be in kg and kg
+-- stays the canonical stored unit. Users opt into pounds via their
+-- preference; input is converted to kg on write and back on read.
+ALTER TABLE users ADD COLUMN weight_unit TEXT NOT NULL DEFAULT 'kg'
+  CHECK (weight_unit IN ('kg', 'lb'));
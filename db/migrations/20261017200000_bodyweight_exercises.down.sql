@@ -0,0 +1,3 @@
+ALTER TABLE exercises DROP COLUMN load_type;
+
+DROP TABLE IF EXISTS bodyweights;
@@ -0,0 +1,21 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS bodyweights (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL,
+  weight REAL NOT NULL CHECK (weight > 0),
+  measured_at TEXT NOT NULL,
+  created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  UNIQUE (user_id, measured_at)
+);
+
+ALTER TABLE exercises ADD COLUMN load_type TEXT NOT NULL DEFAULT 'external'
+  CHECK (load_type IN ('external', 'bodyweight', 'assisted', 'weighted_bodyweight'));
+
+-- Logged weight on these keeps meaning added load (a vest or belt), so no
+-- set data changes; records pick up the bodyweight on their next rebuild.
+UPDATE exercises
+SET load_type = 'bodyweight'
+WHERE owner_user_id IS NULL AND name IN ('Push-Up', 'Pull-Up', 'Hanging Leg Raise');
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_routine_exercises_group;
+DROP INDEX IF EXISTS idx_workout_exercises_group;
+ALTER TABLE routine_exercises DROP COLUMN group_id;
+ALTER TABLE workout_exercises DROP COLUMN group_id;
+DROP TABLE IF EXISTS routine_groups;
+DROP TABLE IF EXISTS workout_groups;
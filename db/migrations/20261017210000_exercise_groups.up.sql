@@ -0,0 +1,34 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS workout_groups (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  workout_id INTEGER NOT NULL,
+  label TEXT NOT NULL,
+  group_type TEXT NOT NULL CHECK (group_type IN ('superset', 'circuit', 'emom')),
+  rounds INTEGER CHECK (rounds IS NULL OR rounds >= 1),
+  rest_seconds INTEGER CHECK (rest_seconds IS NULL OR rest_seconds >= 0),
+
+  FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+  UNIQUE (workout_id, label)
+);
+
+CREATE TABLE IF NOT EXISTS routine_groups (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  routine_id INTEGER NOT NULL,
+  label TEXT NOT NULL,
+  group_type TEXT NOT NULL CHECK (group_type IN ('superset', 'circuit', 'emom')),
+  rounds INTEGER CHECK (rounds IS NULL OR rounds >= 1),
+  rest_seconds INTEGER CHECK (rest_seconds IS NULL OR rest_seconds >= 0),
+
+  FOREIGN KEY (routine_id) REFERENCES routines(id) ON DELETE CASCADE,
+  UNIQUE (routine_id, label)
+);
+
+ALTER TABLE workout_exercises ADD COLUMN group_id INTEGER REFERENCES workout_groups(id) ON DELETE SET NULL;
+ALTER TABLE routine_exercises ADD COLUMN group_id INTEGER REFERENCES routine_groups(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_workout_exercises_group
+  ON workout_exercises(group_id);
+
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_group
+  ON routine_exercises(group_id);
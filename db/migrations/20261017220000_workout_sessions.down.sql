@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_workouts_user_in_progress;
+ALTER TABLE sets DROP COLUMN completed_at;
+ALTER TABLE workouts DROP COLUMN finished_at;
+ALTER TABLE workouts DROP COLUMN started_at;
+ALTER TABLE workouts DROP COLUMN status;
@@ -0,0 +1,10 @@
+ALTER TABLE workouts ADD COLUMN status TEXT NOT NULL DEFAULT 'completed'
+  CHECK (status IN ('in_progress', 'completed', 'abandoned'));
+ALTER TABLE workouts ADD COLUMN started_at TEXT;
+ALTER TABLE workouts ADD COLUMN finished_at TEXT;
+
+ALTER TABLE sets ADD COLUMN completed_at TEXT;
+
+-- at most one live session per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_user_in_progress
+  ON workouts(user_id) WHERE status = 'in_progress';
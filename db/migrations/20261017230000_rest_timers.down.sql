@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS rest_timers;
+DROP TABLE IF EXISTS exercise_rest_defaults;
@@ -0,0 +1,25 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS exercise_rest_defaults (
+  user_id INTEGER NOT NULL,
+  exercise_id INTEGER NOT NULL,
+  rest_seconds INTEGER NOT NULL CHECK (rest_seconds BETWEEN 1 AND 3600),
+
+  PRIMARY KEY (user_id, exercise_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+
+-- the running rest period of a user's live session, at most one per user
+CREATE TABLE IF NOT EXISTS rest_timers (
+  user_id INTEGER PRIMARY KEY,
+  workout_id INTEGER NOT NULL,
+  set_id INTEGER NOT NULL,
+  duration_seconds INTEGER NOT NULL CHECK (duration_seconds >= 1),
+  started_at TEXT NOT NULL,
+  ends_at TEXT NOT NULL,
+
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+  FOREIGN KEY (set_id) REFERENCES sets(id) ON DELETE CASCADE
+);
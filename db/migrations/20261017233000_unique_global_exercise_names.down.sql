@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_exercises_global_name;
@@ -0,0 +1,34 @@
+-- UNIQUE (owner_user_id, name) never applies to global exercises because
+-- their owner is NULL; fold existing duplicates into the oldest one first
+CREATE TEMP TABLE exercise_duplicates AS
+  SELECT e.id AS duplicate_id, k.keep_id
+  FROM exercises e
+  JOIN (
+    SELECT name, MIN(id) AS keep_id
+    FROM exercises
+    WHERE owner_user_id IS NULL
+    GROUP BY name
+  ) k ON k.name = e.name
+  WHERE e.owner_user_id IS NULL AND e.id != k.keep_id;
+
+UPDATE workout_exercises
+SET exercise_id = (SELECT keep_id FROM exercise_duplicates WHERE duplicate_id = exercise_id)
+WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+
+UPDATE routine_exercises
+SET exercise_id = (SELECT keep_id FROM exercise_duplicates WHERE duplicate_id = exercise_id)
+WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+
+UPDATE OR IGNORE exercise_rest_defaults
+SET exercise_id = (SELECT keep_id FROM exercise_duplicates WHERE duplicate_id = exercise_id)
+WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+
+DELETE FROM exercise_rest_defaults WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+DELETE FROM exercise_muscle_groups WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+DELETE FROM personal_records WHERE exercise_id IN (SELECT duplicate_id FROM exercise_duplicates);
+DELETE FROM exercises WHERE id IN (SELECT duplicate_id FROM exercise_duplicates);
+
+DROP TABLE exercise_duplicates;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercises_global_name
+  ON exercises(name) WHERE owner_user_id IS NULL;
@@ -0,0 +1,24 @@
+-- renumber the exercises of workouts that have two at the same position,
+-- keeping their current order
+CREATE TEMP TABLE exercise_renumbering AS
+SELECT id, ROW_NUMBER() OVER (PARTITION BY workout_id ORDER BY exercise_order, id) AS exercise_order
+FROM workout_exercises
+WHERE workout_id IN (
+  SELECT workout_id
+  FROM workout_exercises
+  GROUP BY workout_id, exercise_order
+  HAVING COUNT(*) > 1
+);
+
+-- out of the way first so the new numbers never meet the old ones
+UPDATE workout_exercises SET exercise_order = -id
+WHERE id IN (SELECT id FROM exercise_renumbering);
+
+UPDATE workout_exercises
+SET exercise_order = (SELECT r.exercise_order FROM exercise_renumbering r WHERE r.id = workout_exercises.id)
+WHERE id IN (SELECT id FROM exercise_renumbering);
+
+DROP TABLE exercise_renumbering;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_exercises_order
+  ON workout_exercises(workout_id, exercise_order);
@@ -0,0 +1 @@
+-- metric types stay weight_reps
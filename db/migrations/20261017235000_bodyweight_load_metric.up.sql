@@ -0,0 +1,5 @@
+-- Assisted and weighted bodyweight exercises log their assistance or added
+-- load in weight, which the reps metric type does not accept.
+UPDATE exercises
+SET metric_type = 'weight_reps'
+WHERE metric_type = 'reps' AND load_type IN ('assisted', 'weighted_bodyweight');
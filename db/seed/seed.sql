@@ -31,10 +31,11 @@ SELECT NULL, 'Incline Dumbbell Press',
   (SELECT id FROM categories WHERE name = 'strength'),
   (SELECT id FROM muscle_groups WHERE name = 'chest');
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, load_type)
 SELECT NULL, 'Push-Up',
   (SELECT id FROM categories WHERE name = 'strength'),
-  (SELECT id FROM muscle_groups WHERE name = 'chest');
+  (SELECT id FROM muscle_groups WHERE name = 'chest'),
+  'bodyweight';
 
 INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
 SELECT NULL, 'Deadlift',
@@ -46,10 +47,11 @@ SELECT NULL, 'Barbell Row',
   (SELECT id FROM categories WHERE name = 'strength'),
   (SELECT id FROM muscle_groups WHERE name = 'back');
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, load_type)
 SELECT NULL, 'Pull-Up',
   (SELECT id FROM categories WHERE name = 'strength'),
-  (SELECT id FROM muscle_groups WHERE name = 'back');
+  (SELECT id FROM muscle_groups WHERE name = 'back'),
+  'bodyweight';
 
 INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
 SELECT NULL, 'Lat Pulldown',
@@ -111,30 +113,35 @@ SELECT NULL, 'Triceps Pushdown',
   (SELECT id FROM categories WHERE name = 'strength'),
   (SELECT id FROM muscle_groups WHERE name = 'arms');
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, metric_type)
 SELECT NULL, 'Plank',
   (SELECT id FROM categories WHERE name = 'strength'),
-  (SELECT id FROM muscle_groups WHERE name = 'core');
+  (SELECT id FROM muscle_groups WHERE name = 'core'),
+  'time';
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, load_type)
 SELECT NULL, 'Hanging Leg Raise',
   (SELECT id FROM categories WHERE name = 'strength'),
-  (SELECT id FROM muscle_groups WHERE name = 'core');
+  (SELECT id FROM muscle_groups WHERE name = 'core'),
+  'bodyweight';
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, metric_type)
 SELECT NULL, 'Running',
   (SELECT id FROM categories WHERE name = 'cardio'),
-  (SELECT id FROM muscle_groups WHERE name = 'full_body');
+  (SELECT id FROM muscle_groups WHERE name = 'full_body'),
+  'distance_time';
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, metric_type)
 SELECT NULL, 'Cycling',
   (SELECT id FROM categories WHERE name = 'cardio'),
-  (SELECT id FROM muscle_groups WHERE name = 'full_body');
+  (SELECT id FROM muscle_groups WHERE name = 'full_body'),
+  'distance_time';
 
-INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, metric_type)
 SELECT NULL, 'Rowing Machine',
   (SELECT id FROM categories WHERE name = 'cardio'),
-  (SELECT id FROM muscle_groups WHERE name = 'full_body');
+  (SELECT id FROM muscle_groups WHERE name = 'full_body'),
+  'distance_time';
 
 -- Mobility / Stretching
 INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id)
@@ -157,6 +164,110 @@ SELECT NULL, 'Chest Stretch',
   (SELECT id FROM categories WHERE name = 'stretching'),
   (SELECT id FROM muscle_groups WHERE name = 'chest');
 
+-- Calisthenics
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, load_type)
+SELECT NULL, 'Dip',
+  (SELECT id FROM categories WHERE name = 'strength'),
+  (SELECT id FROM muscle_groups WHERE name = 'chest'),
+  'weighted_bodyweight';
+
+INSERT OR IGNORE INTO exercises (owner_user_id, name, category_id, muscle_group_id, load_type)
+SELECT NULL, 'Assisted Pull-Up',
+  (SELECT id FROM categories WHERE name = 'strength'),
+  (SELECT id FROM muscle_groups WHERE name = 'back'),
+  'assisted';
+
+-- Secondary muscle groups
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Bench Press'
+  AND mg.name IN ('shoulders', 'arms');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Incline Dumbbell Press'
+  AND mg.name IN ('shoulders', 'arms');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Push-Up'
+  AND mg.name IN ('shoulders', 'arms', 'core');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Deadlift'
+  AND mg.name IN ('legs', 'glutes', 'core');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Barbell Row'
+  AND mg.name IN ('arms');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Pull-Up'
+  AND mg.name IN ('arms');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Lat Pulldown'
+  AND mg.name IN ('arms');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Back Squat'
+  AND mg.name IN ('glutes', 'core');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Front Squat'
+  AND mg.name IN ('glutes', 'core');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Romanian Deadlift'
+  AND mg.name IN ('legs', 'back');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Lunge'
+  AND mg.name IN ('glutes');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Leg Press'
+  AND mg.name IN ('glutes');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Hip Thrust'
+  AND mg.name IN ('legs');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Overhead Press'
+  AND mg.name IN ('arms', 'core');
+
+INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+SELECT e.id, mg.id
+FROM exercises e, muscle_groups mg
+WHERE e.owner_user_id IS NULL AND e.name = 'Face Pull'
+  AND mg.name IN ('back');
+
 
 COMMIT;
 
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	Service *services.AnalyticsService
+}
+
+func NewAnalyticsHandler(service *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{Service: service}
+}
+
+func (h *AnalyticsHandler) GetVolume(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetVolume] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	q := models.VolumeQuery{
+		Period:  ctx.Query("period"),
+		GroupBy: ctx.Query("groupBy"),
+		From:    ctx.Query("from"),
+		To:      ctx.Query("to"),
+		Unit:    ctx.Query("unit"),
+	}
+	if raw := ctx.Query("days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "days must be a number"})
+			return
+		}
+		q.Days = days
+	}
+	if q.ExcludeWarmups, err = parseBoolQuery(ctx, "excludeWarmups"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	volume, err := h.Service.GetVolume(userId, q)
+	if err != nil {
+		log.Printf("[GetVolume] failed user=%d: %v", userId, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to compute volume"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, volume)
+}
+
+func (h *AnalyticsHandler) GetCalendar(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetCalendar] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	excludeWarmups, err := parseBoolQuery(ctx, "excludeWarmups")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := h.Service.GetCalendar(userId, ctx.Query("from"), ctx.Query("to"), ctx.Query("unit"), excludeWarmups)
+	if err != nil {
+		log.Printf("[GetCalendar] failed user=%d: %v", userId, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build calendar"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, calendar)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"workout-tracker/internal/repo"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BodyweightHandler struct {
+	Service *services.BodyweightService
+}
+
+func NewBodyweightHandler(service *services.BodyweightService) *BodyweightHandler {
+	return &BodyweightHandler{Service: service}
+}
+
+type bodyweightRequest struct {
+	Weight     float64 `json:"weight" binding:"required"`
+	Unit       string  `json:"unit"`
+	MeasuredAt string  `json:"measuredAt"`
+}
+
+func (h *BodyweightHandler) LogBodyweight(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[LogBodyweight] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req bodyweightRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[LogBodyweight] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	entry, err := h.Service.LogBodyweight(userId, req.Weight, req.Unit, req.MeasuredAt)
+	if err != nil {
+		log.Printf("[LogBodyweight] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrDuplicate) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "a bodyweight is already logged at this time"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, validationError(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entry)
+}
+
+func (h *BodyweightHandler) ListBodyweights(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListBodyweights] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	entries, err := h.Service.ListBodyweights(userId, ctx.Query("from"), ctx.Query("to"), ctx.Query("unit"))
+	if err != nil {
+		log.Printf("[ListBodyweights] failed user=%d: %v", userId, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list bodyweights"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"bodyweights": entries})
+}
+
+func (h *BodyweightHandler) DeleteBodyweight(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteBodyweight] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteBodyweight] invalid id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid bodyweight id"})
+		return
+	}
+
+	if err := h.Service.DeleteBodyweight(userId, id); err != nil {
+		log.Printf("[DeleteBodyweight] failed user=%d id=%d: %v", userId, id, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "bodyweight not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete bodyweight"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"workout-tracker/internal/repo"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	Service *services.CategoryService
+}
+
+func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
+	return &CategoryHandler{Service: service}
+}
+
+type nameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (h *CategoryHandler) ListCategories(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListCategories] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	categories, err := h.Service.ListCategories(userId)
+	if err != nil {
+		log.Printf("[ListCategories] failed user=%d: %v", userId, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list categories"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+func (h *CategoryHandler) GetCategory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetCategory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	categoryId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[GetCategory] invalid category id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	category, err := h.Service.GetCategory(userId, categoryId)
+	if err != nil {
+		log.Printf("[GetCategory] failed user=%d category=%d: %v", userId, categoryId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get category"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) CreateCategory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CreateCategory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req nameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[CreateCategory] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	category, err := h.Service.CreateCategory(userId, req.Name)
+	if err != nil {
+		log.Printf("[CreateCategory] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrDuplicate) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "category with this name already exists"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, category)
+}
+
+func (h *CategoryHandler) RenameCategory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[RenameCategory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	categoryId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[RenameCategory] invalid category id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	var req nameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[RenameCategory] bad request user=%d category=%d: %v", userId, categoryId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	category, err := h.Service.RenameCategory(userId, categoryId, req.Name)
+	if err != nil {
+		log.Printf("[RenameCategory] failed user=%d category=%d: %v", userId, categoryId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "category with this name already exists"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) DeleteCategory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteCategory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	categoryId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteCategory] invalid category id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid category id"})
+		return
+	}
+
+	if err := h.Service.DeleteCategory(categoryId); err != nil {
+		log.Printf("[DeleteCategory] failed user=%d category=%d: %v", userId, categoryId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		case errors.Is(err, services.ErrCategoryInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete category"})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
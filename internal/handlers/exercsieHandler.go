@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
 	"workout-tracker/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -16,11 +21,230 @@ func NewExerciseHandler(service *services.ExerciseService) *ExerciseHandler {
 	return &ExerciseHandler{Service: service}
 }
 
+type exerciseRequest struct {
+	Name          string `json:"name" binding:"required"`
+	CategoryId    int64  `json:"categoryId" binding:"required"`
+	MuscleGroupId *int64 `json:"muscleGroupId"`
+	MetricType    string `json:"metricType"`
+	LoadType      string `json:"loadType"`
+}
+
+type secondaryMuscleGroupsRequest struct {
+	MuscleGroupIds []int64 `json:"muscleGroupIds"`
+}
+
+func parseOptionalIDQuery(ctx *gin.Context, name string) (*int64, error) {
+	raw := strings.TrimSpace(ctx.Query(name))
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
 func (h *ExerciseHandler) ListAllExercises(ctx *gin.Context) {
-	exercises, err := h.Service.ListAllExercises()
+	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
-		log.Printf("[ListAllExercises] error %s", err)
+		log.Printf("[ListAllExercises] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	filter := models.ExerciseFilter{
+		Query:  ctx.Query("q"),
+		Prefix: ctx.Query("match") == "prefix",
+		Owner:  ctx.Query("owner"),
+		Sort:   ctx.Query("sort"),
+		Order:  ctx.Query("order"),
+		Cursor: ctx.Query("cursor"),
+	}
+	if filter.CategoryId, err = parseOptionalIDQuery(ctx, "categoryId"); err != nil {
+		log.Printf("[ListAllExercises] invalid categoryId user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid categoryId"})
+		return
+	}
+	if filter.MuscleGroupId, err = parseOptionalIDQuery(ctx, "muscleGroupId"); err != nil {
+		log.Printf("[ListAllExercises] invalid muscleGroupId user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid muscleGroupId"})
+		return
+	}
+
+	limit, limErr := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if limErr != nil {
+		log.Printf("[ListAllExercises] invalid limit user=%d: %v", userId, limErr)
+		limit = 50
+	}
+	offset, offErr := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if offErr != nil {
+		log.Printf("[ListAllExercises] invalid offset user=%d: %v", userId, offErr)
+		offset = 0
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	exercises, err := h.Service.ListAllExercises(userId, filter)
+	if err != nil {
+		log.Printf("[ListAllExercises] error user=%d: %s", userId, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	ctx.JSON(http.StatusOK, exercises)
+}
+
+func (h *ExerciseHandler) CreateExercise(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CreateExercise] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req exerciseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[CreateExercise] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	exercise, err := h.Service.CreateExercise(userId, req.Name, req.CategoryId, req.MuscleGroupId, req.MetricType, req.LoadType)
+	if err != nil {
+		log.Printf("[CreateExercise] failed user=%d: %v", userId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "exercise with this name already exists"})
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create exercise"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, exercise)
+}
+
+func (h *ExerciseHandler) UpdateExercise(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[UpdateExercise] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"exercises": exercises})
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[UpdateExercise] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	var req exerciseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[UpdateExercise] bad request user=%d exercise=%d: %v", userId, exerciseId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	updated, err := h.Service.UpdateExercise(userId, exerciseId, req.Name, req.CategoryId, req.MuscleGroupId, req.MetricType, req.LoadType)
+	if err != nil {
+		log.Printf("[UpdateExercise] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "global exercises are read-only"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "exercise with this name already exists"})
+		case errors.Is(err, services.ErrMetricTypeInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update exercise"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (h *ExerciseHandler) SetSecondaryMuscleGroups(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[SetSecondaryMuscleGroups] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[SetSecondaryMuscleGroups] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	var req secondaryMuscleGroupsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[SetSecondaryMuscleGroups] bad request user=%d exercise=%d: %v", userId, exerciseId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	updated, err := h.Service.SetSecondaryMuscleGroups(userId, exerciseId, req.MuscleGroupIds)
+	if err != nil {
+		log.Printf("[SetSecondaryMuscleGroups] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "global exercises are read-only"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (h *ExerciseHandler) DeleteExercise(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteExercise] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteExercise] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	if err := h.Service.DeleteExercise(userId, exerciseId); err != nil {
+		log.Printf("[DeleteExercise] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "global exercises are read-only"})
+		case errors.Is(err, services.ErrExerciseInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete exercise"})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HistoryHandler struct {
+	Service *services.HistoryService
+}
+
+func NewHistoryHandler(service *services.HistoryService) *HistoryHandler {
+	return &HistoryHandler{Service: service}
+}
+
+func (h *HistoryHandler) GetExerciseHistory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetExerciseHistory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[GetExerciseHistory] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	filter := models.HistoryFilter{
+		FromRaw: ctx.Query("from"),
+		ToRaw:   ctx.Query("to"),
+		Cursor:  ctx.Query("cursor"),
+		Unit:    ctx.Query("unit"),
+	}
+	if filter.ExcludeWarmups, err = parseBoolQuery(ctx, "excludeWarmups"); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, limErr := strconv.Atoi(ctx.DefaultQuery("limit", "25"))
+	if limErr != nil {
+		log.Printf("[GetExerciseHistory] invalid limit user=%d: %v", userId, limErr)
+		limit = 25
+	}
+	offset, offErr := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if offErr != nil {
+		log.Printf("[GetExerciseHistory] invalid offset user=%d: %v", userId, offErr)
+		offset = 0
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+
+	history, err := h.Service.GetExerciseHistory(userId, exerciseId, ctx.Query("formula"), filter)
+	if err != nil {
+		log.Printf("[GetExerciseHistory] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get exercise history"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
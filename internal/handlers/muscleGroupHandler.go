@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"workout-tracker/internal/repo"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MuscleGroupHandler struct {
+	Service *services.MuscleGroupService
+}
+
+func NewMuscleGroupHandler(service *services.MuscleGroupService) *MuscleGroupHandler {
+	return &MuscleGroupHandler{Service: service}
+}
+
+func (h *MuscleGroupHandler) ListMuscleGroups(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListMuscleGroups] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	muscleGroups, err := h.Service.ListMuscleGroups(userId)
+	if err != nil {
+		log.Printf("[ListMuscleGroups] failed user=%d: %v", userId, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list muscle groups"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"muscleGroups": muscleGroups})
+}
+
+func (h *MuscleGroupHandler) GetMuscleGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetMuscleGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	muscleGroupId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[GetMuscleGroup] invalid muscle group id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid muscle group id"})
+		return
+	}
+
+	muscleGroup, err := h.Service.GetMuscleGroup(userId, muscleGroupId)
+	if err != nil {
+		log.Printf("[GetMuscleGroup] failed user=%d muscleGroup=%d: %v", userId, muscleGroupId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "muscle group not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get muscle group"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, muscleGroup)
+}
+
+func (h *MuscleGroupHandler) CreateMuscleGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CreateMuscleGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req nameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[CreateMuscleGroup] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	muscleGroup, err := h.Service.CreateMuscleGroup(userId, req.Name)
+	if err != nil {
+		log.Printf("[CreateMuscleGroup] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrDuplicate) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "muscle group with this name already exists"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, muscleGroup)
+}
+
+func (h *MuscleGroupHandler) RenameMuscleGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[RenameMuscleGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	muscleGroupId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[RenameMuscleGroup] invalid muscle group id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid muscle group id"})
+		return
+	}
+
+	var req nameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[RenameMuscleGroup] bad request user=%d muscleGroup=%d: %v", userId, muscleGroupId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	muscleGroup, err := h.Service.RenameMuscleGroup(userId, muscleGroupId, req.Name)
+	if err != nil {
+		log.Printf("[RenameMuscleGroup] failed user=%d muscleGroup=%d: %v", userId, muscleGroupId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "muscle group not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "muscle group with this name already exists"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, muscleGroup)
+}
+
+func (h *MuscleGroupHandler) DeleteMuscleGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteMuscleGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	muscleGroupId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteMuscleGroup] invalid muscle group id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid muscle group id"})
+		return
+	}
+
+	if err := h.Service.DeleteMuscleGroup(muscleGroupId); err != nil {
+		log.Printf("[DeleteMuscleGroup] failed user=%d muscleGroup=%d: %v", userId, muscleGroupId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "muscle group not found"})
+		case errors.Is(err, services.ErrMuscleGroupInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete muscle group"})
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
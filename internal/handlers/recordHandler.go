@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecordHandler struct {
+	Service *services.RecordService
+}
+
+func NewRecordHandler(service *services.RecordService) *RecordHandler {
+	return &RecordHandler{Service: service}
+}
+
+func (h *RecordHandler) ListRecords(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListRecords] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	records, err := h.Service.ListRecords(userId, ctx.Query("unit"))
+	if err != nil {
+		log.Printf("[ListRecords] failed user=%d: %v", userId, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list records"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+func (h *RecordHandler) ListExerciseRecords(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListExerciseRecords] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[ListExerciseRecords] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	records, err := h.Service.ListExerciseRecords(userId, exerciseId, ctx.Query("unit"))
+	if err != nil {
+		log.Printf("[ListExerciseRecords] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+			return
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list records"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"records": records})
+}
+
+func (h *RecordHandler) GetE1RMHistory(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetE1RMHistory] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[GetE1RMHistory] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	series, err := h.Service.GetE1RMHistory(userId, exerciseId, ctx.Query("formula"), ctx.Query("unit"), ctx.Query("from"), ctx.Query("to"))
+	if err != nil {
+		log.Printf("[GetE1RMHistory] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get e1rm history"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, series)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventsKeepAlive is how often an idle event stream gets a comment line so
+// proxies and clients don't drop it.
+const eventsKeepAlive = 25 * time.Second
+
+type RestTimerHandler struct {
+	Service *services.RestTimerService
+}
+
+func NewRestTimerHandler(service *services.RestTimerService) *RestTimerHandler {
+	return &RestTimerHandler{Service: service}
+}
+
+type restDefaultRequest struct {
+	RestSeconds int `json:"restSeconds" binding:"required"`
+}
+
+func (h *RestTimerHandler) ListRestDefaults(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListRestDefaults] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	defaults, err := h.Service.ListRestDefaults(userId)
+	if err != nil {
+		log.Printf("[ListRestDefaults] failed user=%d: %v", userId, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list rest defaults"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"restDefaults": defaults})
+}
+
+func (h *RestTimerHandler) SetRestDefault(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[SetRestDefault] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[SetRestDefault] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	var req restDefaultRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[SetRestDefault] bad request user=%d exercise=%d: %v", userId, exerciseId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	d, err := h.Service.SetRestDefault(userId, exerciseId, req.RestSeconds)
+	if err != nil {
+		log.Printf("[SetRestDefault] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set rest default"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, d)
+}
+
+func (h *RestTimerHandler) DeleteRestDefault(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteRestDefault] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	exerciseId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteRestDefault] invalid exercise id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid exercise id"})
+		return
+	}
+
+	if err := h.Service.DeleteRestDefault(userId, exerciseId); err != nil {
+		log.Printf("[DeleteRestDefault] failed user=%d exercise=%d: %v", userId, exerciseId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "rest default not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete rest default"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *RestTimerHandler) GetRestTimer(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetRestTimer] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	timer, err := h.Service.GetRestTimer(userId)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no rest timer running"})
+			return
+		}
+		log.Printf("[GetRestTimer] failed user=%d: %v", userId, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get rest timer"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, timer)
+}
+
+func (h *RestTimerHandler) SkipRest(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[SkipRest] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.Service.SkipRest(userId); err != nil {
+		log.Printf("[SkipRest] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no rest timer running"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to skip rest"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// StreamEvents keeps a Server-Sent Events stream open and forwards the
+// user's rest timer events. A timer already running is sent first as
+// rest_started so a device joining mid-rest is in sync.
+func (h *RestTimerHandler) StreamEvents(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[StreamEvents] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	events, unsubscribe := h.Service.Subscribe(userId)
+	defer unsubscribe()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	if timer, err := h.Service.GetRestTimer(userId); err == nil {
+		ctx.SSEvent(models.RestStarted, models.RestTimerEvent{Type: models.RestStarted, Timer: timer})
+	} else if !errors.Is(err, repo.ErrNotFound) {
+		log.Printf("[StreamEvents] failed to get rest timer user=%d: %v", userId, err)
+	}
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event := <-events:
+			ctx.SSEvent(event.Type, event)
+		case <-keepAlive.C:
+			_, _ = io.WriteString(w, ": keep-alive\n\n")
+		}
+		return true
+	})
+}
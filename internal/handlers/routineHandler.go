@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+	"workout-tracker/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoutineHandler struct {
+	Service *services.RoutineService
+}
+
+func NewRoutineHandler(service *services.RoutineService) *RoutineHandler {
+	return &RoutineHandler{Service: service}
+}
+
+type routineSetRequest struct {
+	SetNumber int      `json:"setNumber" binding:"required"`
+	Reps      *int     `json:"reps"`
+	Weight    *float64 `json:"weight"`
+	Unit      string   `json:"unit"`
+}
+
+type routineExerciseRequest struct {
+	ExerciseId    int64               `json:"exerciseId" binding:"required"`
+	ExerciseOrder int                 `json:"exerciseOrder" binding:"required"`
+	Notes         *string             `json:"notes"`
+	GroupLabel    *string             `json:"groupLabel"`
+	Sets          []routineSetRequest `json:"sets" binding:"dive"`
+}
+
+type routineRequest struct {
+	Name      string                   `json:"name" binding:"required"`
+	Notes     *string                  `json:"notes"`
+	Groups    []exerciseGroupRequest   `json:"groups"`
+	Exercises []routineExerciseRequest `json:"exercises" binding:"dive"`
+}
+
+type startRoutineRequest struct {
+	Timezone *string `json:"timezone"`
+}
+
+func (req routineRequest) toModel() models.Routine {
+	routine := models.Routine{
+		Name:   req.Name,
+		Notes:  req.Notes,
+		Groups: toGroupsModel(req.Groups),
+	}
+	for _, ex := range req.Exercises {
+		re := models.RoutineExercise{
+			ExerciseId:    ex.ExerciseId,
+			ExerciseOrder: ex.ExerciseOrder,
+			Notes:         ex.Notes,
+			GroupLabel:    ex.GroupLabel,
+		}
+		for _, set := range ex.Sets {
+			re.Sets = append(re.Sets, models.RoutineSet{
+				SetNumber: set.SetNumber,
+				Reps:      set.Reps,
+				Weight:    set.Weight,
+				Unit:      set.Unit,
+			})
+		}
+		routine.Exercises = append(routine.Exercises, re)
+	}
+	return routine
+}
+
+func (h *RoutineHandler) CreateRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CreateRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req routineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[CreateRoutine] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	routine, err := h.Service.CreateRoutine(userId, req.toModel())
+	if err != nil {
+		log.Printf("[CreateRoutine] failed user=%d: %v", userId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "routine with this name already exists"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, routine)
+}
+
+func (h *RoutineHandler) ListRoutines(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ListRoutines] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routines, err := h.Service.ListRoutines(userId)
+	if err != nil {
+		log.Printf("[ListRoutines] failed user=%d: %v", userId, err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list routines"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"routines": routines})
+}
+
+func (h *RoutineHandler) GetRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routineId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[GetRoutine] invalid routine id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid routine id"})
+		return
+	}
+
+	routine, err := h.Service.GetRoutine(userId, routineId)
+	if err != nil {
+		log.Printf("[GetRoutine] failed user=%d routine=%d: %v", userId, routineId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "routine not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get routine"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, routine)
+}
+
+func (h *RoutineHandler) UpdateRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[UpdateRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routineId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[UpdateRoutine] invalid routine id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid routine id"})
+		return
+	}
+
+	var req routineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[UpdateRoutine] bad request user=%d routine=%d: %v", userId, routineId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	routine, err := h.Service.UpdateRoutine(userId, routineId, req.toModel())
+	if err != nil {
+		log.Printf("[UpdateRoutine] failed user=%d routine=%d: %v", userId, routineId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "routine not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "routine with this name already exists"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, routine)
+}
+
+func (h *RoutineHandler) DeleteRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routineId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteRoutine] invalid routine id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid routine id"})
+		return
+	}
+
+	if err := h.Service.DeleteRoutine(userId, routineId); err != nil {
+		log.Printf("[DeleteRoutine] failed user=%d routine=%d: %v", userId, routineId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "routine not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete routine"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *RoutineHandler) StartRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[StartRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routineId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[StartRoutine] invalid routine id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid routine id"})
+		return
+	}
+
+	var req startRoutineRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.Printf("[StartRoutine] bad request user=%d routine=%d: %v", userId, routineId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	details, err := h.Service.StartRoutine(userId, routineId, req.Timezone)
+	if err != nil {
+		log.Printf("[StartRoutine] failed user=%d routine=%d: %v", userId, routineId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "routine not found"})
+			return
+		case errors.Is(err, services.ErrWorkoutInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start routine"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, details)
+}
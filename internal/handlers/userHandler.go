@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
 	"workout-tracker/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -131,3 +134,64 @@ func (h *UserHandler) Register(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusCreated, gin.H{"userId": userId})
 }
+
+type preferencesRequest struct {
+	E1RMFormula string `json:"e1rmFormula"`
+	Timezone    string `json:"timezone"`
+	WeightUnit  string `json:"weightUnit"`
+}
+
+func (h *UserHandler) GetPreferences(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetPreferences] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	prefs, err := h.Service.GetPreferences(userId)
+	if err != nil {
+		log.Printf("[GetPreferences] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get preferences"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prefs)
+}
+
+func (h *UserHandler) UpdatePreferences(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[UpdatePreferences] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req preferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[UpdatePreferences] bad request user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	prefs, err := h.Service.UpdatePreferences(userId, models.UserPreferences{
+		E1RMFormula: req.E1RMFormula,
+		Timezone:    req.Timezone,
+		WeightUnit:  req.WeightUnit,
+	})
+	if err != nil {
+		log.Printf("[UpdatePreferences] failed user=%d: %v", userId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prefs)
+}
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"workout-tracker/internal/middleware"
+	"workout-tracker/internal/models"
 	"workout-tracker/internal/repo"
 	"workout-tracker/internal/services"
 
@@ -22,65 +25,228 @@ func NewWorkoutHandler(service *services.WorkoutService) *WorkoutHandler {
 }
 
 func getUserIDFromContext(ctx *gin.Context) (int64, error) {
-	raw, ok := ctx.Get("userId")
-	if !ok {
-		return 0, errors.New("missing userId in context")
-	}
+	return middleware.UserIDFromContext(ctx)
+}
 
-	if s, ok := raw.(string); ok {
-		s = strings.TrimSpace(s)
-		if s == "" {
-			return 0, errors.New("empty userId in context")
-		}
-		return strconv.ParseInt(s, 10, 64)
+func parseIDParam(ctx *gin.Context, name string) (int64, error) {
+	return strconv.ParseInt(ctx.Param(name), 10, 64)
+}
+
+// validationError renders a service validation error, naming the field
+// when the service reported one.
+func validationError(err error) gin.H {
+	var fieldErr *services.FieldError
+	if errors.As(err, &fieldErr) {
+		return gin.H{"error": err.Error(), "field": fieldErr.Field}
 	}
+	return gin.H{"error": err.Error()}
+}
 
-	switch v := raw.(type) {
-	case int64:
-		return v, nil
-	case int:
-		return int64(v), nil
-	default:
-		return 0, errors.New("invalid userId type in context")
+func parseOptionalIntQuery(ctx *gin.Context, name string) (*int, error) {
+	raw := strings.TrimSpace(ctx.Query(name))
+	if raw == "" {
+		return nil, nil
 	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
 }
 
-func parseIDParam(ctx *gin.Context, name string) (int64, error) {
-	return strconv.ParseInt(ctx.Param(name), 10, 64)
+func parseBoolQuery(ctx *gin.Context, name string) (bool, error) {
+	raw := strings.TrimSpace(ctx.Query(name))
+	if raw == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return b, nil
 }
 
 type createWorkoutRequest struct {
-	PerformedAt     string  `json:"performedAt" binding:"required"`
-	DurationMinutes *int    `json:"durationMinutes"`
-	Notes           *string `json:"notes"`
+	PerformedAt     string                          `json:"performedAt" binding:"required"`
+	Timezone        *string                         `json:"timezone"`
+	DurationMinutes *int                            `json:"durationMinutes"`
+	Notes           *string                         `json:"notes"`
+	Groups          []exerciseGroupRequest          `json:"groups"`
+	Exercises       []workoutExerciseDetailsRequest `json:"exercises" binding:"dive"`
+}
+
+type exerciseGroupRequest struct {
+	Label       string `json:"label"`
+	GroupType   string `json:"groupType"`
+	Rounds      *int   `json:"rounds"`
+	RestSeconds *int   `json:"restSeconds"`
+}
+
+type workoutExerciseDetailsRequest struct {
+	Id int64 `json:"id"`
+	addWorkoutExerciseRequest
+	Sets []setDetailsRequest `json:"sets" binding:"dive"`
+}
+
+type setDetailsRequest struct {
+	Id int64 `json:"id"`
+	addSetRequest
+}
+
+type startWorkoutRequest struct {
+	Timezone *string `json:"timezone"`
+	Notes    *string `json:"notes"`
+}
+
+type duplicateWorkoutRequest struct {
+	Timezone              *string `json:"timezone"`
+	ClearSets             bool    `json:"clearSets"`
+	WeightIncreasePercent float64 `json:"weightIncreasePercent"`
+	Completed             bool    `json:"completed"`
+}
+
+func (req duplicateWorkoutRequest) toModel() models.DuplicateOptions {
+	return models.DuplicateOptions{
+		Timezone:              req.Timezone,
+		ClearSets:             req.ClearSets,
+		WeightIncreasePercent: req.WeightIncreasePercent,
+		Completed:             req.Completed,
+	}
 }
 
 type updateWorkoutRequest struct {
 	PerformedAt     string  `json:"performedAt" binding:"required"`
+	Timezone        *string `json:"timezone"`
 	DurationMinutes *int    `json:"durationMinutes"`
 	Notes           *string `json:"notes"`
 }
 
 type addWorkoutExerciseRequest struct {
 	ExerciseId    int64   `json:"exerciseId" binding:"required"`
-	ExerciseOrder int     `json:"exerciseOrder" binding:"required"`
+	ExerciseOrder int     `json:"exerciseOrder"`
 	Notes         *string `json:"notes"`
+	GroupLabel    *string `json:"groupLabel"`
 }
 
 type updateWorkoutExerciseRequest struct {
-	ExerciseOrder int     `json:"exerciseOrder" binding:"required"`
+	ExerciseOrder int     `json:"exerciseOrder"`
 	Notes         *string `json:"notes"`
+	GroupLabel    *string `json:"groupLabel"`
+}
+
+type reorderWorkoutExercisesRequest struct {
+	WorkoutExerciseIds []int64 `json:"workoutExerciseIds" binding:"required"`
 }
 
 type addSetRequest struct {
-	SetNumber int      `json:"setNumber" binding:"required"`
-	Reps      *int     `json:"reps"`
-	Weight    *float64 `json:"weight"`
+	SetNumber int `json:"setNumber"`
+	setValuesRequest
 }
 
+// updateSetRequest may also tick the set off (true) or clear it (false)
+// during a live session.
 type updateSetRequest struct {
-	Reps   *int     `json:"reps"`
-	Weight *float64 `json:"weight"`
+	setValuesRequest
+	Completed *bool `json:"completed"`
+}
+
+type setValuesRequest struct {
+	SetType     string   `json:"setType"`
+	Reps        *int     `json:"reps"`
+	Weight      *float64 `json:"weight"`
+	Unit        string   `json:"unit"`
+	RPE         *float64 `json:"rpe"`
+	RIR         *int     `json:"rir"`
+	Tempo       *string  `json:"tempo"`
+	RestSeconds *int     `json:"restSeconds"`
+
+	DurationSeconds *int     `json:"durationSeconds"`
+	DistanceMeters  *float64 `json:"distanceMeters"`
+	Calories        *int     `json:"calories"`
+	AvgHeartRate    *int     `json:"avgHeartRate"`
+	MaxHeartRate    *int     `json:"maxHeartRate"`
+}
+
+func (req addSetRequest) toModel() models.Set {
+	set := req.setValuesRequest.toModel()
+	set.SetNumber = req.SetNumber
+	return set
+}
+
+func (req setValuesRequest) toModel() models.Set {
+	return models.Set{
+		SetType:     req.SetType,
+		Reps:        req.Reps,
+		Weight:      req.Weight,
+		Unit:        req.Unit,
+		RPE:         req.RPE,
+		RIR:         req.RIR,
+		Tempo:       req.Tempo,
+		RestSeconds: req.RestSeconds,
+
+		DurationSeconds: req.DurationSeconds,
+		DistanceMeters:  req.DistanceMeters,
+		Calories:        req.Calories,
+		AvgHeartRate:    req.AvgHeartRate,
+		MaxHeartRate:    req.MaxHeartRate,
+	}
+}
+
+func (req exerciseGroupRequest) toModel() models.ExerciseGroup {
+	return models.ExerciseGroup{
+		Label:       req.Label,
+		GroupType:   req.GroupType,
+		Rounds:      req.Rounds,
+		RestSeconds: req.RestSeconds,
+	}
+}
+
+// toGroupsModel keeps a missing list nil, which PUT /details reads as
+// "leave the groups alone".
+func toGroupsModel(groups []exerciseGroupRequest) []models.ExerciseGroup {
+	if groups == nil {
+		return nil
+	}
+	out := make([]models.ExerciseGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, g.toModel())
+	}
+	return out
+}
+
+func toWorkoutExercisesModel(exercises []workoutExerciseDetailsRequest) []models.WorkoutExerciseWithSets {
+	var out []models.WorkoutExerciseWithSets
+	for _, ex := range exercises {
+		we := models.WorkoutExerciseWithSets{
+			WorkoutExercise: models.WorkoutExercise{
+				Id:            ex.Id,
+				ExerciseId:    ex.ExerciseId,
+				ExerciseOrder: ex.ExerciseOrder,
+				Notes:         ex.Notes,
+				GroupLabel:    ex.GroupLabel,
+			},
+		}
+		for _, set := range ex.Sets {
+			s := set.toModel()
+			s.Id = set.Id
+			we.Sets = append(we.Sets, s)
+		}
+		out = append(out, we)
+	}
+	return out
+}
+
+func (req createWorkoutRequest) toModel() models.WorkoutWithDetails {
+	return models.WorkoutWithDetails{
+		Workout: models.Workout{
+			PerformedAt:     req.PerformedAt,
+			Timezone:        req.Timezone,
+			DurationMinutes: req.DurationMinutes,
+			Notes:           req.Notes,
+		},
+		Groups:    toGroupsModel(req.Groups),
+		Exercises: toWorkoutExercisesModel(req.Exercises),
+	}
 }
 
 func (h *WorkoutHandler) CreateWorkout(ctx *gin.Context) {
@@ -98,10 +264,14 @@ func (h *WorkoutHandler) CreateWorkout(ctx *gin.Context) {
 		return
 	}
 
-	workout, err := h.Service.CreateWorkout(userId, req.PerformedAt, req.DurationMinutes, req.Notes)
+	workout, err := h.Service.CreateWorkout(userId, req.toModel())
 	if err != nil {
 		log.Printf("[CreateWorkout] failed user=%d: %v", userId, err)
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrExerciseNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, validationError(err))
 		return
 	}
 
@@ -116,6 +286,37 @@ func (h *WorkoutHandler) ListWorkouts(ctx *gin.Context) {
 		return
 	}
 
+	filter := models.WorkoutFilter{
+		Query:   ctx.Query("q"),
+		Status:  ctx.Query("status"),
+		Sort:    ctx.Query("sort"),
+		Order:   ctx.Query("order"),
+		Cursor:  ctx.Query("cursor"),
+		FromRaw: ctx.Query("from"),
+		ToRaw:   ctx.Query("to"),
+	}
+	for name, dst := range map[string]**int64{
+		"exerciseId":    &filter.ExerciseId,
+		"categoryId":    &filter.CategoryId,
+		"muscleGroupId": &filter.MuscleGroupId,
+	} {
+		if *dst, err = parseOptionalIDQuery(ctx, name); err != nil {
+			log.Printf("[ListWorkouts] invalid %s user=%d: %v", name, userId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+			return
+		}
+	}
+	for name, dst := range map[string]**int{
+		"minDuration": &filter.MinDuration,
+		"maxDuration": &filter.MaxDuration,
+	} {
+		if *dst, err = parseOptionalIntQuery(ctx, name); err != nil {
+			log.Printf("[ListWorkouts] invalid %s user=%d: %v", name, userId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+			return
+		}
+	}
+
 	limit, limErr := strconv.Atoi(ctx.DefaultQuery("limit", "25"))
 	if limErr != nil {
 		log.Printf("[ListWorkouts] invalid limit user=%d: %v", userId, limErr)
@@ -126,10 +327,16 @@ func (h *WorkoutHandler) ListWorkouts(ctx *gin.Context) {
 		log.Printf("[ListWorkouts] invalid offset user=%d: %v", userId, offErr)
 		offset = 0
 	}
+	filter.Limit = limit
+	filter.Offset = offset
 
-	workouts, err := h.Service.ListWorkouts(userId, limit, offset)
+	workouts, err := h.Service.ListWorkouts(userId, filter)
 	if err != nil {
 		log.Printf("[ListWorkouts] failed user=%d limit=%d offset=%d: %v", userId, limit, offset, err)
+		if errors.Is(err, services.ErrInvalidQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workouts"})
 		return
 	}
@@ -181,12 +388,16 @@ func (h *WorkoutHandler) GetWorkoutDetails(ctx *gin.Context) {
 		return
 	}
 
-	details, err := h.Service.GetWorkoutDetails(userId, workoutId)
+	details, err := h.Service.GetWorkoutDetails(userId, workoutId, ctx.Query("formula"), ctx.Query("unit"))
 	if err != nil {
 		log.Printf("[GetWorkoutDetails] failed user=%d workout=%d: %v", userId, workoutId, err)
-		if errors.Is(err, repo.ErrNotFound) {
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
 			return
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get workout details"})
 		return
@@ -195,6 +406,195 @@ func (h *WorkoutHandler) GetWorkoutDetails(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, details)
 }
 
+func (h *WorkoutHandler) StartWorkout(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[StartWorkout] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req startWorkoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.Printf("[StartWorkout] bad request user=%d: %v", userId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	details, err := h.Service.StartWorkout(userId, req.Timezone, req.Notes)
+	if err != nil {
+		log.Printf("[StartWorkout] failed user=%d: %v", userId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, services.ErrWorkoutInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start workout"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, details)
+}
+
+func (h *WorkoutHandler) GetActiveWorkout(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[GetActiveWorkout] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	details, err := h.Service.GetActiveWorkout(userId, ctx.Query("unit"))
+	if err != nil {
+		log.Printf("[GetActiveWorkout] failed user=%d: %v", userId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no workout in progress"})
+			return
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get active workout"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, details)
+}
+
+func (h *WorkoutHandler) FinishWorkout(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[FinishWorkout] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	workoutId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[FinishWorkout] invalid workout id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		return
+	}
+
+	workout, err := h.Service.FinishWorkout(userId, workoutId)
+	if err != nil {
+		log.Printf("[FinishWorkout] failed user=%d workout=%d: %v", userId, workoutId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
+			return
+		case errors.Is(err, services.ErrWorkoutNotInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to finish workout"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, workout)
+}
+
+func (h *WorkoutHandler) DuplicateWorkout(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DuplicateWorkout] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	workoutId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DuplicateWorkout] invalid workout id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		return
+	}
+
+	var req duplicateWorkoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.Printf("[DuplicateWorkout] bad request user=%d workout=%d: %v", userId, workoutId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	details, err := h.Service.DuplicateWorkout(userId, workoutId, req.toModel())
+	if err != nil {
+		log.Printf("[DuplicateWorkout] failed user=%d workout=%d: %v", userId, workoutId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
+			return
+		case errors.Is(err, services.ErrWorkoutInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to duplicate workout"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, details)
+}
+
+// RepeatRoutine duplicates the last completed workout of a routine and takes
+// the same options as DuplicateWorkout.
+func (h *WorkoutHandler) RepeatRoutine(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[RepeatRoutine] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	routineId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[RepeatRoutine] invalid routine id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid routine id"})
+		return
+	}
+
+	var req duplicateWorkoutRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			log.Printf("[RepeatRoutine] bad request user=%d routine=%d: %v", userId, routineId, err)
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+	}
+
+	details, err := h.Service.RepeatRoutine(userId, routineId, req.toModel())
+	if err != nil {
+		log.Printf("[RepeatRoutine] failed user=%d routine=%d: %v", userId, routineId, err)
+		var fieldErr *services.FieldError
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "no completed workout of this routine"})
+			return
+		case errors.Is(err, services.ErrWorkoutInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		case errors.As(err, &fieldErr):
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to repeat routine"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, details)
+}
+
 func (h *WorkoutHandler) UpdateWorkout(ctx *gin.Context) {
 	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -217,20 +617,66 @@ func (h *WorkoutHandler) UpdateWorkout(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := h.Service.UpdateWorkout(userId, workoutId, req.PerformedAt, req.DurationMinutes, req.Notes)
+	updated, err := h.Service.UpdateWorkout(userId, workoutId, models.Workout{
+		PerformedAt:     req.PerformedAt,
+		Timezone:        req.Timezone,
+		DurationMinutes: req.DurationMinutes,
+		Notes:           req.Notes,
+	})
 	if err != nil {
 		log.Printf("[UpdateWorkout] failed user=%d workout=%d: %v", userId, workoutId, err)
 		if errors.Is(err, repo.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, validationError(err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, updated)
 }
 
+func (h *WorkoutHandler) ReplaceWorkoutDetails(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ReplaceWorkoutDetails] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	workoutId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[ReplaceWorkoutDetails] invalid workout id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		return
+	}
+
+	var req createWorkoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[ReplaceWorkoutDetails] bad request user=%d workout=%d: %v", userId, workoutId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	details, err := h.Service.ReplaceWorkoutDetails(userId, workoutId, req.toModel())
+	if err != nil {
+		log.Printf("[ReplaceWorkoutDetails] failed user=%d workout=%d: %v", userId, workoutId, err)
+		switch {
+		case errors.Is(err, services.ErrExerciseNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "workout was changed concurrently, reload and try again"})
+		default:
+			ctx.JSON(http.StatusBadRequest, validationError(err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, details)
+}
+
 func (h *WorkoutHandler) DeleteWorkout(ctx *gin.Context) {
 	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -281,9 +727,13 @@ func (h *WorkoutHandler) AddExerciseToWorkout(ctx *gin.Context) {
 		return
 	}
 
-	we, err := h.Service.AddExerciseToWorkout(userId, workoutId, req.ExerciseId, req.ExerciseOrder, req.Notes)
+	we, err := h.Service.AddExerciseToWorkout(userId, workoutId, req.ExerciseId, req.ExerciseOrder, req.Notes, req.GroupLabel)
 	if err != nil {
 		log.Printf("[AddExerciseToWorkout] failed user=%d workout=%d exercise=%d: %v", userId, workoutId, req.ExerciseId, err)
+		if errors.Is(err, services.ErrExerciseNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "exercise not found"})
+			return
+		}
 		if errors.Is(err, repo.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
 			return
@@ -317,7 +767,7 @@ func (h *WorkoutHandler) UpdateWorkoutExercise(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := h.Service.UpdateWorkoutExercise(userId, workoutExerciseId, req.ExerciseOrder, req.Notes)
+	updated, err := h.Service.UpdateWorkoutExercise(userId, workoutExerciseId, req.ExerciseOrder, req.Notes, req.GroupLabel)
 	if err != nil {
 		log.Printf("[UpdateWorkoutExercise] failed user=%d workoutExercise=%d: %v", userId, workoutExerciseId, err)
 		if errors.Is(err, repo.ErrNotFound) {
@@ -331,6 +781,42 @@ func (h *WorkoutHandler) UpdateWorkoutExercise(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, updated)
 }
 
+func (h *WorkoutHandler) ReorderWorkoutExercises(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[ReorderWorkoutExercises] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	workoutId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[ReorderWorkoutExercises] invalid workout id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		return
+	}
+
+	var req reorderWorkoutExercisesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[ReorderWorkoutExercises] bad request user=%d workout=%d: %v", userId, workoutId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	exercises, err := h.Service.ReorderWorkoutExercises(userId, workoutId, req.WorkoutExerciseIds)
+	if err != nil {
+		log.Printf("[ReorderWorkoutExercises] failed user=%d workout=%d: %v", userId, workoutId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"exercises": exercises})
+}
+
 func (h *WorkoutHandler) DeleteWorkoutExercise(ctx *gin.Context) {
 	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -359,6 +845,112 @@ func (h *WorkoutHandler) DeleteWorkoutExercise(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+func (h *WorkoutHandler) CreateWorkoutGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CreateWorkoutGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	workoutId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[CreateWorkoutGroup] invalid workout id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid workout id"})
+		return
+	}
+
+	var req exerciseGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[CreateWorkoutGroup] bad request user=%d workout=%d: %v", userId, workoutId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	group, err := h.Service.CreateWorkoutGroup(userId, workoutId, req.toModel())
+	if err != nil {
+		log.Printf("[CreateWorkoutGroup] failed user=%d workout=%d: %v", userId, workoutId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "group label already used in this workout"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, group)
+}
+
+func (h *WorkoutHandler) UpdateWorkoutGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[UpdateWorkoutGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	groupId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[UpdateWorkoutGroup] invalid group id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	var req exerciseGroupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		log.Printf("[UpdateWorkoutGroup] bad request user=%d group=%d: %v", userId, groupId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	group, err := h.Service.UpdateWorkoutGroup(userId, groupId, req.toModel())
+	if err != nil {
+		log.Printf("[UpdateWorkoutGroup] failed user=%d group=%d: %v", userId, groupId, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		case errors.Is(err, repo.ErrDuplicate):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "group label already used in this workout"})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, group)
+}
+
+func (h *WorkoutHandler) DeleteWorkoutGroup(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[DeleteWorkoutGroup] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	groupId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[DeleteWorkoutGroup] invalid group id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid group id"})
+		return
+	}
+
+	if err := h.Service.DeleteWorkoutGroup(userId, groupId); err != nil {
+		log.Printf("[DeleteWorkoutGroup] failed user=%d group=%d: %v", userId, groupId, err)
+		if errors.Is(err, repo.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete group"})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 func (h *WorkoutHandler) AddSet(ctx *gin.Context) {
 	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -381,13 +973,17 @@ func (h *WorkoutHandler) AddSet(ctx *gin.Context) {
 		return
 	}
 
-	set, err := h.Service.AddSet(userId, workoutExerciseId, req.SetNumber, req.Reps, req.Weight)
+	set, err := h.Service.AddSet(userId, workoutExerciseId, req.toModel())
 	if err != nil {
 		log.Printf("[AddSet] failed user=%d workoutExercise=%d setNumber=%d: %v", userId, workoutExerciseId, req.SetNumber, err)
 		if errors.Is(err, repo.ErrNotFound) {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout exercise not found"})
 			return
 		}
+		if errors.Is(err, repo.ErrDuplicate) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "setNumber already used for this exercise"})
+			return
+		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -417,12 +1013,16 @@ func (h *WorkoutHandler) UpdateSet(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := h.Service.UpdateSet(userId, setId, req.Reps, req.Weight)
+	updated, err := h.Service.UpdateSet(userId, setId, req.toModel(), req.Completed)
 	if err != nil {
 		log.Printf("[UpdateSet] failed user=%d set=%d: %v", userId, setId, err)
-		if errors.Is(err, repo.ErrNotFound) {
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "set not found"})
 			return
+		case errors.Is(err, services.ErrWorkoutNotInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
 		}
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -431,6 +1031,41 @@ func (h *WorkoutHandler) UpdateSet(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, updated)
 }
 
+// CompleteSet ticks a set off on POST and clears the tick on DELETE.
+func (h *WorkoutHandler) CompleteSet(ctx *gin.Context) {
+	userId, err := getUserIDFromContext(ctx)
+	if err != nil {
+		log.Printf("[CompleteSet] unauthorized: %v", err)
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	setId, err := parseIDParam(ctx, "id")
+	if err != nil {
+		log.Printf("[CompleteSet] invalid set id user=%d: %v", userId, err)
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid set id"})
+		return
+	}
+
+	completed := ctx.Request.Method == http.MethodPost
+	set, err := h.Service.CompleteSet(userId, setId, completed)
+	if err != nil {
+		log.Printf("[CompleteSet] failed user=%d set=%d completed=%t: %v", userId, setId, completed, err)
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "set not found"})
+			return
+		case errors.Is(err, services.ErrWorkoutNotInProgress):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update set"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, set)
+}
+
 func (h *WorkoutHandler) DeleteSet(ctx *gin.Context) {
 	userId, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -474,12 +1109,22 @@ func (h *WorkoutHandler) GetWorkoutReport(ctx *gin.Context) {
 		return
 	}
 
-	report, err := h.Service.GetWorkoutReport(userId, workoutId)
+	excludeWarmups, err := parseBoolQuery(ctx, "excludeWarmups")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.Service.GetWorkoutReport(userId, workoutId, ctx.Query("formula"), ctx.Query("unit"), excludeWarmups)
 	if err != nil {
 		log.Printf("[GetWorkoutReport] failed user=%d workout=%d: %v", userId, workoutId, err)
-		if errors.Is(err, repo.ErrNotFound) {
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "workout not found"})
 			return
+		case errors.Is(err, services.ErrInvalidQuery):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate workout report"})
 		return
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"workout-tracker/internal/services"
@@ -43,3 +46,54 @@ func AuthMiddleware(userService *services.UserService) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// UserIDFromContext returns the id AuthMiddleware stored for the caller.
+func UserIDFromContext(ctx *gin.Context) (int64, error) {
+	raw, ok := ctx.Get("userId")
+	if !ok {
+		return 0, errors.New("missing userId in context")
+	}
+
+	if s, ok := raw.(string); ok {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return 0, errors.New("empty userId in context")
+		}
+		return strconv.ParseInt(s, 10, 64)
+	}
+
+	switch v := raw.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return 0, errors.New("invalid userId type in context")
+	}
+}
+
+func RequireAdmin(userService *services.UserService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userId, err := UserIDFromContext(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			ctx.Abort()
+			return
+		}
+
+		isAdmin, err := userService.IsAdmin(userId)
+		if err != nil {
+			log.Printf("[RequireAdmin] failed user=%d: %v", userId, err)
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			ctx.Abort()
+			return
+		}
+		if !isAdmin {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "admin only"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
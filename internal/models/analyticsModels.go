@@ -0,0 +1,98 @@
+package models
+
+type AnalyticsSet struct {
+	WorkoutId       int64
+	PerformedAt     string
+	ExerciseId      int64
+	ExerciseName    string
+	CategoryId      int64
+	CategoryName    string
+	MuscleGroupId   *int64
+	MuscleGroupName *string
+	Reps            *int
+	Load            *float64
+}
+
+type NamedRef struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type VolumeQuery struct {
+	Period  string
+	Days    int
+	GroupBy string
+	From    string
+	To      string
+	Unit    string
+
+	ExcludeWarmups bool
+}
+
+type VolumeBreakdown struct {
+	Id     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Sets   int     `json:"sets"`
+	Reps   int     `json:"reps"`
+	Volume float64 `json:"volume"`
+}
+
+type VolumeTotals struct {
+	Workouts int     `json:"workouts"`
+	Sets     int     `json:"sets"`
+	Reps     int     `json:"reps"`
+	Volume   float64 `json:"volume"`
+}
+
+type VolumeBucket struct {
+	Label string `json:"label"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	VolumeTotals
+	Breakdown []VolumeBreakdown `json:"breakdown"`
+}
+
+type VolumeAnalytics struct {
+	Period     string         `json:"period"`
+	Days       int            `json:"days,omitempty"`
+	GroupBy    string         `json:"groupBy"`
+	Timezone   string         `json:"timezone"`
+	WeightUnit string         `json:"weightUnit"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Totals     VolumeTotals   `json:"totals"`
+	Buckets    []VolumeBucket `json:"buckets"`
+}
+
+type CalendarWorkout struct {
+	Id              int64
+	PerformedAt     string
+	DurationMinutes *int
+	Volume          float64
+}
+
+type CalendarDay struct {
+	Date            string  `json:"date"`
+	Workouts        int     `json:"workouts"`
+	DurationMinutes int     `json:"durationMinutes"`
+	Volume          float64 `json:"volume"`
+}
+
+type StreakStats struct {
+	CurrentDaily    int     `json:"currentDaily"`
+	LongestDaily    int     `json:"longestDaily"`
+	CurrentWeekly   int     `json:"currentWeekly"`
+	LongestWeekly   int     `json:"longestWeekly"`
+	WorkoutsPerWeek float64 `json:"workoutsPerWeek"`
+}
+
+type Calendar struct {
+	Timezone      string        `json:"timezone"`
+	WeightUnit    string        `json:"weightUnit"`
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	TotalWorkouts int           `json:"totalWorkouts"`
+	ActiveDays    int           `json:"activeDays"`
+	Streaks       StreakStats   `json:"streaks"`
+	Days          []CalendarDay `json:"days"`
+}
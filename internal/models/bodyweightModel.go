@@ -0,0 +1,10 @@
+package models
+
+type Bodyweight struct {
+	Id         int64   `json:"id"`
+	UserId     int64   `json:"userId"`
+	Weight     float64 `json:"weight"`
+	Unit       string  `json:"unit"`
+	MeasuredAt string  `json:"measuredAt"`
+	CreatedAt  string  `json:"createdAt"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+const (
+	RecordMaxWeight         = "max_weight"
+	RecordMaxReps           = "max_reps"
+	RecordBestE1RM          = "best_e1rm"
+	RecordBestSetVolume     = "best_set_volume"
+	RecordBestSessionVolume = "best_session_volume"
+)
+
+type PersonalRecord struct {
+	Id           int64    `json:"id"`
+	UserId       int64    `json:"userId"`
+	ExerciseId   int64    `json:"exerciseId"`
+	ExerciseName string   `json:"exerciseName"`
+	RecordType   string   `json:"recordType"`
+	Value        float64  `json:"value"`
+	Reps         *int     `json:"reps,omitempty"`
+	Weight       *float64 `json:"weight,omitempty"`
+	Unit         string   `json:"unit,omitempty"`
+	SetId        *int64   `json:"setId,omitempty"`
+	WorkoutId    int64    `json:"workoutId"`
+	AchievedAt   string   `json:"achievedAt"`
+}
+
+type SetHistoryEntry struct {
+	SetId             int64
+	WorkoutId         int64
+	WorkoutExerciseId int64
+	PerformedAt       string
+	SetNumber         int
+	Reps              *int
+	Weight            *float64
+	Load              *float64
+	LoadType          string
+}
+
+type E1RMPoint struct {
+	WorkoutId   int64   `json:"workoutId"`
+	PerformedAt string  `json:"performedAt"`
+	E1RM        float64 `json:"e1rm"`
+	Reps        int     `json:"reps"`
+	Weight      float64 `json:"weight"`
+}
+
+type E1RMSeries struct {
+	ExerciseId int64       `json:"exerciseId"`
+	Formula    string      `json:"formula"`
+	WeightUnit string      `json:"weightUnit"`
+	Points     []E1RMPoint `json:"points"`
+}
+
+type HistoryFilter struct {
+	FromRaw string
+	ToRaw   string
+	From    *time.Time
+	To      *time.Time
+	Limit   int
+	Offset  int
+	Cursor  string
+	After   *Cursor
+	Unit    string
+
+	ExcludeWarmups bool
+}
+
+type ExerciseHistoryEntry struct {
+	WorkoutId   int64    `json:"workoutId"`
+	PerformedAt string   `json:"performedAt"`
+	Notes       *string  `json:"notes,omitempty"`
+	Sets        []Set    `json:"sets"`
+	TopSet      *Set     `json:"topSet,omitempty"`
+	TotalSets   int      `json:"totalSets"`
+	TotalReps   int      `json:"totalReps"`
+	TotalVolume float64  `json:"totalVolume"`
+	BestE1RM    *float64 `json:"bestE1rm,omitempty"`
+}
+
+type ExerciseHistory struct {
+	ExerciseId int64                  `json:"exerciseId"`
+	Formula    string                 `json:"formula"`
+	WeightUnit string                 `json:"weightUnit"`
+	Entries    []ExerciseHistoryEntry `json:"entries"`
+	Total      int                    `json:"total"`
+	Limit      int                    `json:"limit"`
+	Offset     int                    `json:"offset"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+}
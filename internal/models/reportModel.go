@@ -3,24 +3,50 @@ package models
 type WorkoutReportExercise struct {
 	ExerciseId   int64    `json:"exerciseId"`
 	ExerciseName string   `json:"exerciseName"`
+	MetricType   string   `json:"metricType"`
+	LoadType     string   `json:"loadType"`
 	SetsCount    int      `json:"setsCount"`
 	TotalReps    int      `json:"totalReps"`
 	MaxWeight    *float64 `json:"maxWeight,omitempty"`
 	TotalVolume  float64  `json:"totalVolume"`
+	BestE1RM     *float64 `json:"bestE1rm,omitempty"`
+	AvgRPE       *float64 `json:"avgRpe,omitempty"`
+
+	TotalDurationSeconds int      `json:"totalDurationSeconds,omitempty"`
+	TotalDistanceMeters  float64  `json:"totalDistanceMeters,omitempty"`
+	PaceSecondsPerKm     *float64 `json:"paceSecondsPerKm,omitempty"`
+}
+
+type WorkoutReportGroup struct {
+	Label           string  `json:"label"`
+	GroupType       string  `json:"groupType"`
+	Rounds          *int    `json:"rounds,omitempty"`
+	RestSeconds     *int    `json:"restSeconds,omitempty"`
+	ExerciseIds     []int64 `json:"exerciseIds"`
+	TotalSets       int     `json:"totalSets"`
+	TotalVolume     float64 `json:"totalVolume"`
+	CompletedRounds int     `json:"completedRounds"`
 }
 
 type WorkoutReport struct {
-	WorkoutId       int64   `json:"workoutId"`
-	UserId          int64   `json:"userId"`
-	PerformedAt     string  `json:"performedAt"`
-	DurationMinutes *int    `json:"durationMinutes,omitempty"`
-	Notes           *string `json:"notes,omitempty"`
-	CreatedAt       string  `json:"createdAt"`
+	WorkoutId       int64    `json:"workoutId"`
+	UserId          int64    `json:"userId"`
+	PerformedAt     string   `json:"performedAt"`
+	DurationMinutes *int     `json:"durationMinutes,omitempty"`
+	Notes           *string  `json:"notes,omitempty"`
+	CreatedAt       string   `json:"createdAt"`
+	E1RMFormula     string   `json:"e1rmFormula"`
+	WeightUnit      string   `json:"weightUnit"`
+	Bodyweight      *float64 `json:"bodyweight,omitempty"`
 
-	TotalExercises int     `json:"totalExercises"`
-	TotalSets      int     `json:"totalSets"`
-	TotalReps      int     `json:"totalReps"`
-	TotalVolume    float64 `json:"totalVolume"`
+	TotalExercises       int     `json:"totalExercises"`
+	TotalSets            int     `json:"totalSets"`
+	TotalReps            int     `json:"totalReps"`
+	TotalVolume          float64 `json:"totalVolume"`
+	TotalDurationSeconds int     `json:"totalDurationSeconds,omitempty"`
+	TotalDistanceMeters  float64 `json:"totalDistanceMeters,omitempty"`
 
-	Exercises []WorkoutReportExercise `json:"exercises"`
+	Groups          []WorkoutReportGroup    `json:"groups"`
+	Exercises       []WorkoutReportExercise `json:"exercises"`
+	PersonalRecords []PersonalRecord        `json:"personalRecords"`
 }
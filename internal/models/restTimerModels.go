@@ -0,0 +1,31 @@
+package models
+
+// RestDefault is a user's default rest after a set of one exercise, used
+// when the completed set has no restSeconds of its own.
+type RestDefault struct {
+	ExerciseId  int64 `json:"exerciseId"`
+	RestSeconds int   `json:"restSeconds"`
+}
+
+// RestTimer is the rest period running after a completed set of a live
+// session. Times are UTC.
+type RestTimer struct {
+	WorkoutId        int64  `json:"workoutId"`
+	SetId            int64  `json:"setId"`
+	DurationSeconds  int    `json:"durationSeconds"`
+	StartedAt        string `json:"startedAt"`
+	EndsAt           string `json:"endsAt"`
+	RemainingSeconds int    `json:"remainingSeconds"`
+}
+
+const (
+	RestStarted   = "rest_started"
+	RestEnded     = "rest_ended"
+	RestCancelled = "rest_cancelled"
+)
+
+// RestTimerEvent is pushed to every connected device of the user.
+type RestTimerEvent struct {
+	Type  string    `json:"type"`
+	Timer RestTimer `json:"timer"`
+}
@@ -0,0 +1,32 @@
+package models
+
+type RoutineSet struct {
+	Id                int64    `json:"id"`
+	RoutineExerciseId int64    `json:"routineExerciseId"`
+	SetNumber         int      `json:"setNumber"`
+	Reps              *int     `json:"reps,omitempty"`
+	Weight            *float64 `json:"weight,omitempty"`
+	Unit              string   `json:"unit,omitempty"`
+}
+
+type RoutineExercise struct {
+	Id            int64        `json:"id"`
+	RoutineId     int64        `json:"routineId"`
+	ExerciseId    int64        `json:"exerciseId"`
+	ExerciseName  string       `json:"exerciseName"`
+	ExerciseOrder int          `json:"exerciseOrder"`
+	Notes         *string      `json:"notes,omitempty"`
+	GroupLabel    *string      `json:"groupLabel,omitempty"`
+	Sets          []RoutineSet `json:"sets"`
+}
+
+type Routine struct {
+	Id        int64             `json:"id"`
+	UserId    int64             `json:"userId"`
+	Name      string            `json:"name"`
+	Notes     *string           `json:"notes,omitempty"`
+	CreatedAt string            `json:"createdAt"`
+	UpdatedAt string            `json:"updatedAt"`
+	Groups    []ExerciseGroup   `json:"groups"`
+	Exercises []RoutineExercise `json:"exercises"`
+}
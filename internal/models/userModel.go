@@ -19,3 +19,16 @@ type RefreshToken struct {
 	RevokedAt         *time.Time
 	ReplacedByTokenId *int64
 }
+
+const (
+	UnitKg = "kg"
+	UnitLb = "lb"
+)
+
+var WeightUnits = []string{UnitKg, UnitLb}
+
+type UserPreferences struct {
+	E1RMFormula string `json:"e1rmFormula"`
+	Timezone    string `json:"timezone"`
+	WeightUnit  string `json:"weightUnit"`
+}
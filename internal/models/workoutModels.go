@@ -1,12 +1,74 @@
 package models
 
+import "time"
+
 type Workout struct {
-	Id              int64   `json:"id"`
-	UserId          int64   `json:"userId"`
-	PerformedAt     string  `json:"performedAt"`
-	DurationMinutes *int    `json:"durationMinutes,omitempty"`
-	Notes           *string `json:"notes,omitempty"`
-	CreatedAt       string  `json:"createdAt"`
+	Id               int64   `json:"id"`
+	UserId           int64   `json:"userId"`
+	PerformedAt      string  `json:"performedAt"`
+	PerformedAtLocal string  `json:"performedAtLocal"`
+	UTCOffset        string  `json:"utcOffset"`
+	Timezone         *string `json:"timezone,omitempty"`
+	DurationMinutes  *int    `json:"durationMinutes,omitempty"`
+	Notes            *string `json:"notes,omitempty"`
+	RoutineId        *int64  `json:"routineId,omitempty"`
+	Status           string  `json:"status"`
+	StartedAt        *string `json:"startedAt,omitempty"`
+	FinishedAt       *string `json:"finishedAt,omitempty"`
+	CreatedAt        string  `json:"createdAt"`
+}
+
+const (
+	WorkoutInProgress = "in_progress"
+	WorkoutCompleted  = "completed"
+	WorkoutAbandoned  = "abandoned"
+)
+
+var WorkoutStatuses = []string{WorkoutInProgress, WorkoutCompleted, WorkoutAbandoned}
+
+// Cursor marks the last row of a page: its sort key and id. Sort records
+// the sort/order it was issued for.
+type Cursor struct {
+	Sort string `json:"s"`
+	Key  any    `json:"k"`
+	Id   int64  `json:"i"`
+}
+
+type WorkoutFilter struct {
+	FromRaw       string
+	ToRaw         string
+	From          *time.Time
+	To            *time.Time
+	ExerciseId    *int64
+	CategoryId    *int64
+	MuscleGroupId *int64
+	Query         string
+	MinDuration   *int
+	MaxDuration   *int
+	Status        string
+	Sort          string
+	Order         string
+	Limit         int
+	Offset        int
+	Cursor        string
+	After         *Cursor
+}
+
+// DuplicateOptions controls how a workout is copied. Without Completed the
+// copy is a live session.
+type DuplicateOptions struct {
+	Timezone              *string
+	ClearSets             bool
+	WeightIncreasePercent float64
+	Completed             bool
+}
+
+type WorkoutList struct {
+	Workouts   []Workout `json:"workouts"`
+	Total      int       `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextCursor string    `json:"nextCursor,omitempty"`
 }
 
 type WorkoutExercise struct {
@@ -15,31 +77,159 @@ type WorkoutExercise struct {
 	ExerciseId    int64   `json:"exerciseId"`
 	ExerciseOrder int     `json:"exerciseOrder"`
 	Notes         *string `json:"notes,omitempty"`
+	GroupLabel    *string `json:"groupLabel,omitempty"`
+}
+
+const (
+	GroupSuperset = "superset"
+	GroupCircuit  = "circuit"
+	GroupEMOM     = "emom"
+)
+
+var GroupTypes = []string{GroupSuperset, GroupCircuit, GroupEMOM}
+
+// ExerciseGroup ties exercises of one workout or routine together, e.g. a
+// superset. Members reference it by label.
+type ExerciseGroup struct {
+	Id          int64  `json:"id"`
+	Label       string `json:"label"`
+	GroupType   string `json:"groupType"`
+	Rounds      *int   `json:"rounds,omitempty"`
+	RestSeconds *int   `json:"restSeconds,omitempty"`
 }
 
+const (
+	SetTypeWarmup  = "warmup"
+	SetTypeNormal  = "normal"
+	SetTypeDrop    = "drop"
+	SetTypeFailure = "failure"
+	SetTypeAMRAP   = "amrap"
+	SetTypeBackoff = "backoff"
+)
+
+var SetTypes = []string{SetTypeWarmup, SetTypeNormal, SetTypeDrop, SetTypeFailure, SetTypeAMRAP, SetTypeBackoff}
+
 type Set struct {
 	Id                int64    `json:"id"`
 	WorkoutExerciseId int64    `json:"workoutExerciseId"`
 	SetNumber         int      `json:"setNumber"`
+	SetType           string   `json:"setType"`
 	Reps              *int     `json:"reps,omitempty"`
 	Weight            *float64 `json:"weight,omitempty"`
+	Load              *float64 `json:"load,omitempty"`
+	Unit              string   `json:"unit,omitempty"`
+	RPE               *float64 `json:"rpe,omitempty"`
+	RIR               *int     `json:"rir,omitempty"`
+	Tempo             *string  `json:"tempo,omitempty"`
+	RestSeconds       *int     `json:"restSeconds,omitempty"`
+	DurationSeconds   *int     `json:"durationSeconds,omitempty"`
+	DistanceMeters    *float64 `json:"distanceMeters,omitempty"`
+	Calories          *int     `json:"calories,omitempty"`
+	AvgHeartRate      *int     `json:"avgHeartRate,omitempty"`
+	MaxHeartRate      *int     `json:"maxHeartRate,omitempty"`
+	CompletedAt       *string  `json:"completedAt,omitempty"`
+	E1RM              *float64 `json:"e1rm,omitempty"`
+
+	NewRecords []PersonalRecord `json:"newRecords,omitempty"`
+	RestTimer  *RestTimer       `json:"restTimer,omitempty"`
 }
 
 type WorkoutExerciseWithSets struct {
 	WorkoutExercise
-	Sets []Set `json:"sets"`
+	Group    *ExerciseGroup    `json:"group,omitempty"`
+	Sets     []Set             `json:"sets"`
+	Previous *PreviousExercise `json:"previous,omitempty"`
+}
+
+// PreviousExercise is what the user did the last time they performed an
+// exercise before a workout.
+type PreviousExercise struct {
+	WorkoutId   int64  `json:"workoutId"`
+	PerformedAt string `json:"performedAt"`
+	Sets        []Set  `json:"sets"`
 }
 
 type WorkoutWithDetails struct {
 	Workout
+	Groups    []ExerciseGroup           `json:"groups"`
 	Exercises []WorkoutExerciseWithSets `json:"exercises"`
 }
 
+type WorkoutDetailsDiff struct {
+	Workout Workout
+	// Groups replaces the workout's groups; nil leaves them as they are.
+	Groups                   []ExerciseGroup
+	DeleteWorkoutExerciseIds []int64
+	UpdateWorkoutExercises   []WorkoutExercise
+	InsertWorkoutExercises   []WorkoutExerciseWithSets
+	DeleteSetIds             []int64
+	UpdateSets               []Set
+	InsertSets               []Set
+}
+
+const (
+	MetricWeightReps   = "weight_reps"
+	MetricReps         = "reps"
+	MetricTime         = "time"
+	MetricDistanceTime = "distance_time"
+)
+
+var MetricTypes = []string{MetricWeightReps, MetricReps, MetricTime, MetricDistanceTime}
+
+const (
+	LoadExternal           = "external"
+	LoadBodyweight         = "bodyweight"
+	LoadAssisted           = "assisted"
+	LoadWeightedBodyweight = "weighted_bodyweight"
+)
+
+var LoadTypes = []string{LoadExternal, LoadBodyweight, LoadAssisted, LoadWeightedBodyweight}
+
 type Exercise struct {
+	Id              int64   `json:"id"`
+	OwnerUserId     *int64  `json:"ownerUserId,omitempty"`
+	Name            string  `json:"name"`
+	CategoryId      int64   `json:"categoryId"`
+	CategoryName    string  `json:"categoryName"`
+	MuscleGroupId   *int64  `json:"muscleGroupId,omitempty"`
+	MuscleGroupName *string `json:"muscleGroupName,omitempty"`
+	MetricType      string  `json:"metricType"`
+	LoadType        string  `json:"loadType"`
+	CreatedAt       string  `json:"createdAt"`
+
+	SecondaryMuscleGroupIds []int64 `json:"secondaryMuscleGroupIds"`
+}
+
+type Category struct {
+	Id            int64  `json:"id"`
+	Name          string `json:"name"`
+	ExerciseCount int    `json:"exerciseCount"`
+}
+
+type MuscleGroup struct {
 	Id            int64  `json:"id"`
-	OwnerUserId   *int64 `json:"ownerUserId,omitempty"`
 	Name          string `json:"name"`
-	CategoryId    int64  `json:"categoryId"`
-	MuscleGroupId *int64 `json:"muscleGroupId,omitempty"`
-	CreatedAt     string `json:"createdAt"`
+	ExerciseCount int    `json:"exerciseCount"`
+}
+
+type ExerciseFilter struct {
+	Query         string
+	Prefix        bool
+	CategoryId    *int64
+	MuscleGroupId *int64
+	Owner         string
+	Sort          string
+	Order         string
+	Limit         int
+	Offset        int
+	Cursor        string
+	After         *Cursor
+}
+
+type ExerciseList struct {
+	Exercises  []Exercise `json:"exercises"`
+	Total      int        `json:"total"`
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 }
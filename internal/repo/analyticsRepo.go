@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"database/sql"
+	"workout-tracker/internal/models"
+)
+
+type AnalyticsRepo struct {
+	DB *sql.DB
+}
+
+func NewAnalyticsRepo(db *sql.DB) *AnalyticsRepo {
+	return &AnalyticsRepo{DB: db}
+}
+
+func (repo *AnalyticsRepo) ListSets(userId int64, from, to string, excludeWarmups bool) ([]models.AnalyticsSet, error) {
+	rows, err := repo.DB.Query(`
+		SELECT
+			w.id, w.performed_at,
+			e.id, e.name,
+			c.id, c.name,
+			mg.id, mg.name,
+			s.reps, `+setVolumeLoadExpr+`
+		FROM sets s
+		JOIN workout_exercises we ON we.id = s.workout_exercise_id
+		JOIN workouts w ON w.id = we.workout_id
+		JOIN exercises e ON e.id = we.exercise_id
+		JOIN categories c ON c.id = e.category_id
+		LEFT JOIN muscle_groups mg ON mg.id = e.muscle_group_id
+		WHERE w.user_id = ? AND w.status <> ? AND w.performed_at >= ? AND w.performed_at <= ?`+warmupCondition(excludeWarmups)+`
+		ORDER BY w.performed_at ASC, w.id ASC
+	`, userId, models.WorkoutAbandoned, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.AnalyticsSet
+	for rows.Next() {
+		var s models.AnalyticsSet
+		if err := rows.Scan(
+			&s.WorkoutId, &s.PerformedAt,
+			&s.ExerciseId, &s.ExerciseName,
+			&s.CategoryId, &s.CategoryName,
+			&s.MuscleGroupId, &s.MuscleGroupName,
+			&s.Reps, &s.Load,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (repo *AnalyticsRepo) ListSecondaryMuscleGroups(userId int64) (map[int64][]models.NamedRef, error) {
+	rows, err := repo.DB.Query(`
+		SELECT emg.exercise_id, mg.id, mg.name
+		FROM exercise_muscle_groups emg
+		JOIN muscle_groups mg ON mg.id = emg.muscle_group_id
+		JOIN exercises e ON e.id = emg.exercise_id
+		WHERE e.owner_user_id IS NULL OR e.owner_user_id = ?
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64][]models.NamedRef{}
+	for rows.Next() {
+		var exerciseId int64
+		var mg models.NamedRef
+		if err := rows.Scan(&exerciseId, &mg.Id, &mg.Name); err != nil {
+			return nil, err
+		}
+		out[exerciseId] = append(out[exerciseId], mg)
+	}
+	return out, rows.Err()
+}
+
+func (repo *AnalyticsRepo) ListCalendarWorkouts(userId int64, from, to string, excludeWarmups bool) ([]models.CalendarWorkout, error) {
+	rows, err := repo.DB.Query(`
+		SELECT
+			w.id,
+			w.performed_at,
+			w.duration_minutes,
+			COALESCE((
+				SELECT SUM(COALESCE(s.reps, 0) * COALESCE(`+setVolumeLoadExpr+`, 0))
+				FROM workout_exercises we
+				JOIN exercises e ON e.id = we.exercise_id
+				JOIN sets s ON s.workout_exercise_id = we.id
+				WHERE we.workout_id = w.id`+warmupCondition(excludeWarmups)+`
+			), 0)
+		FROM workouts w
+		WHERE w.user_id = ? AND w.status <> ? AND w.performed_at >= ? AND w.performed_at <= ?
+		ORDER BY w.performed_at ASC, w.id ASC
+	`, userId, models.WorkoutAbandoned, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.CalendarWorkout
+	for rows.Next() {
+		var w models.CalendarWorkout
+		if err := rows.Scan(&w.Id, &w.PerformedAt, &w.DurationMinutes, &w.Volume); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (repo *AnalyticsRepo) ListWorkoutTimes(userId int64) ([]string, error) {
+	rows, err := repo.DB.Query(`
+		SELECT performed_at
+		FROM workouts
+		WHERE user_id = ? AND status <> ?
+		ORDER BY performed_at ASC
+	`, userId, models.WorkoutAbandoned)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var performedAt string
+		if err := rows.Scan(&performedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, performedAt)
+	}
+	return out, rows.Err()
+}
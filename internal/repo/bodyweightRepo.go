@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+	"workout-tracker/internal/models"
+)
+
+type BodyweightRepo struct {
+	DB *sql.DB
+}
+
+func NewBodyweightRepo(db *sql.DB) *BodyweightRepo {
+	return &BodyweightRepo{DB: db}
+}
+
+// bodyweightAt is the bodyweight of the workout aliased w: the latest entry
+// logged at or before it, else the first one logged after it.
+const bodyweightAt = `COALESCE(
+	(
+		SELECT bw.weight
+		FROM bodyweights bw
+		WHERE bw.user_id = w.user_id AND bw.measured_at <= w.performed_at
+		ORDER BY bw.measured_at DESC
+		LIMIT 1
+	),
+	(
+		SELECT bw.weight
+		FROM bodyweights bw
+		WHERE bw.user_id = w.user_id
+		ORDER BY bw.measured_at ASC
+		LIMIT 1
+	)
+)`
+
+// setLoadExpr is the load moved by a set for bodyweight exercises (aliases
+// s, e, w) and NULL for external ones, where the logged weight is the load.
+// Assistance is subtracted from bodyweight and never goes below zero. The
+// bodyweight is looked up once per set through the derived table bw.
+const setLoadExpr = `CASE e.load_type
+	WHEN '` + models.LoadExternal + `' THEN NULL
+	ELSE (
+		SELECT CASE e.load_type
+			WHEN '` + models.LoadAssisted + `' THEN MAX(COALESCE(bw.weight, 0) - COALESCE(s.weight, 0), 0)
+			ELSE COALESCE(bw.weight + COALESCE(s.weight, 0), s.weight)
+		END
+		FROM (SELECT ` + bodyweightAt + ` AS weight) bw
+	)
+END`
+
+// setVolumeLoadExpr is the weight a set counts with in volume totals.
+const setVolumeLoadExpr = `COALESCE(` + setLoadExpr + `, s.weight)`
+
+const bodyweightColumns = `id, user_id, weight, measured_at, created_at`
+
+func scanBodyweight(row rowScanner) (models.Bodyweight, error) {
+	var b models.Bodyweight
+	err := row.Scan(&b.Id, &b.UserId, &b.Weight, &b.MeasuredAt, &b.CreatedAt)
+	return b, err
+}
+
+func (repo *BodyweightRepo) Create(userId int64, weight float64, measuredAt string) (models.Bodyweight, error) {
+	res, err := repo.DB.Exec(`
+		INSERT INTO bodyweights (user_id, weight, measured_at)
+		VALUES (?, ?, ?)
+	`, userId, weight, measuredAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Bodyweight{}, ErrDuplicate
+		}
+		return models.Bodyweight{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Bodyweight{}, err
+	}
+
+	return repo.GetById(userId, id)
+}
+
+func (repo *BodyweightRepo) GetById(userId, id int64) (models.Bodyweight, error) {
+	b, err := scanBodyweight(repo.DB.QueryRow(`
+		SELECT `+bodyweightColumns+`
+		FROM bodyweights
+		WHERE id = ? AND user_id = ?
+	`, id, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Bodyweight{}, ErrNotFound
+		}
+		return models.Bodyweight{}, err
+	}
+	return b, nil
+}
+
+func (repo *BodyweightRepo) List(userId int64, from, to *time.Time) ([]models.Bodyweight, error) {
+	where := []string{"user_id = ?"}
+	args := []any{userId}
+	if from != nil {
+		where = append(where, "measured_at >= ?")
+		args = append(args, from.UTC().Format(time.RFC3339))
+	}
+	if to != nil {
+		where = append(where, "measured_at <= ?")
+		args = append(args, to.UTC().Format(time.RFC3339))
+	}
+
+	rows, err := repo.DB.Query(`
+		SELECT `+bodyweightColumns+`
+		FROM bodyweights
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY measured_at DESC, id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Bodyweight{}
+	for rows.Next() {
+		b, err := scanBodyweight(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// Delete removes an entry and returns it, so callers know which workouts
+// its weight applied to.
+func (repo *BodyweightRepo) Delete(userId, id int64) (models.Bodyweight, error) {
+	b, err := scanBodyweight(repo.DB.QueryRow(`
+		DELETE FROM bodyweights
+		WHERE id = ? AND user_id = ?
+		RETURNING `+bodyweightColumns, id, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Bodyweight{}, ErrNotFound
+		}
+		return models.Bodyweight{}, err
+	}
+	return b, nil
+}
+
+// HasEntryBefore reports whether the user logged a bodyweight before
+// measuredAt. Workouts older than the first entry fall back to it.
+func (repo *BodyweightRepo) HasEntryBefore(userId int64, measuredAt string) (bool, error) {
+	var exists bool
+	err := repo.DB.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM bodyweights WHERE user_id = ? AND measured_at < ?)
+	`, userId, measuredAt).Scan(&exists)
+	return exists, err
+}
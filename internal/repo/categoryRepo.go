@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"workout-tracker/internal/models"
+)
+
+type CategoryRepo struct {
+	DB *sql.DB
+}
+
+func NewCategoryRepo(db *sql.DB) *CategoryRepo {
+	return &CategoryRepo{DB: db}
+}
+
+func (repo *CategoryRepo) ListCategories(userId int64) ([]models.Category, error) {
+	rows, err := repo.DB.Query(`
+		SELECT c.id, c.name, COUNT(e.id) AS exercise_count
+		FROM categories c
+		LEFT JOIN exercises e
+			ON e.category_id = c.id AND (e.owner_user_id IS NULL OR e.owner_user_id = ?)
+		GROUP BY c.id, c.name
+		ORDER BY c.name ASC
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Category{}
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.Id, &c.Name, &c.ExerciseCount); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (repo *CategoryRepo) GetCategoryById(userId, id int64) (models.Category, error) {
+	var c models.Category
+	err := repo.DB.QueryRow(`
+		SELECT c.id, c.name, COUNT(e.id) AS exercise_count
+		FROM categories c
+		LEFT JOIN exercises e
+			ON e.category_id = c.id AND (e.owner_user_id IS NULL OR e.owner_user_id = ?)
+		WHERE c.id = ?
+		GROUP BY c.id, c.name
+	`, userId, id).Scan(&c.Id, &c.Name, &c.ExerciseCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Category{}, ErrNotFound
+		}
+		return models.Category{}, err
+	}
+	return c, nil
+}
+
+func (repo *CategoryRepo) CreateCategory(userId int64, name string) (models.Category, error) {
+	res, err := repo.DB.Exec(`INSERT INTO categories (name) VALUES (?)`, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Category{}, ErrDuplicate
+		}
+		return models.Category{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	return repo.GetCategoryById(userId, id)
+}
+
+func (repo *CategoryRepo) RenameCategory(userId, id int64, name string) (models.Category, error) {
+	res, err := repo.DB.Exec(`UPDATE categories SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Category{}, ErrDuplicate
+		}
+		return models.Category{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.Category{}, err
+	}
+	if affected == 0 {
+		return models.Category{}, ErrNotFound
+	}
+
+	return repo.GetCategoryById(userId, id)
+}
+
+func (repo *CategoryRepo) DeleteCategory(id int64) error {
+	res, err := repo.DB.Exec(`DELETE FROM categories WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *CategoryRepo) CountExerciseReferences(id int64) (int, error) {
+	var count int
+	err := repo.DB.QueryRow(`SELECT COUNT(*) FROM exercises WHERE category_id = ?`, id).Scan(&count)
+	return count, err
+}
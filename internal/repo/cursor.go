@@ -0,0 +1,37 @@
+package repo
+
+import "workout-tracker/internal/models"
+
+// keysetCondition limits a query ordered by (column, idColumn) to the rows
+// after cursor. Nullable columns are expected to sort NULLS LAST.
+func keysetCondition(column, idColumn, direction string, nullable bool, cursor *models.Cursor) (string, []any) {
+	op := ">"
+	if direction == "DESC" {
+		op = "<"
+	}
+	if cursor.Key == nil {
+		return "(" + column + " IS NULL AND " + idColumn + " " + op + " ?)", []any{cursor.Id}
+	}
+	cond := column + " " + op + " ? OR (" + column + " = ? AND " + idColumn + " " + op + " ?)"
+	if nullable {
+		cond += " OR " + column + " IS NULL"
+	}
+	return "(" + cond + ")", []any{cursor.Key, cursor.Key, cursor.Id}
+}
+
+// extraScanner scans trailing columns that are not part of the model.
+type extraScanner struct {
+	rowScanner
+	extra []any
+}
+
+func (s extraScanner) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
+
+func cursorKey(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"slices"
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction string
+		nullable  bool
+		cursor    models.Cursor
+		wantCond  string
+		wantArgs  []any
+	}{
+		{
+			name:      "ascending",
+			direction: "ASC",
+			cursor:    models.Cursor{Key: "b", Id: 4},
+			wantCond:  "(w.k > ? OR (w.k = ? AND w.id > ?))",
+			wantArgs:  []any{"b", "b", int64(4)},
+		},
+		{
+			name:      "descending nullable keeps the null tail",
+			direction: "DESC",
+			nullable:  true,
+			cursor:    models.Cursor{Key: 30.0, Id: 9},
+			wantCond:  "(w.k < ? OR (w.k = ? AND w.id < ?) OR w.k IS NULL)",
+			wantArgs:  []any{30.0, 30.0, int64(9)},
+		},
+		{
+			name:      "inside the null tail",
+			direction: "DESC",
+			nullable:  true,
+			cursor:    models.Cursor{Key: nil, Id: 9},
+			wantCond:  "(w.k IS NULL AND w.id < ?)",
+			wantArgs:  []any{int64(9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, args := keysetCondition("w.k", "w.id", tt.direction, tt.nullable, &tt.cursor)
+			if cond != tt.wantCond || !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("got %q %v, want %q %v", cond, args, tt.wantCond, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -2,9 +2,16 @@ package repo
 
 import (
 	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
 	"workout-tracker/internal/models"
+
+	"github.com/mattn/go-sqlite3"
 )
 
+var ErrDuplicate = errors.New("already exists")
+
 type ExerciseRepo struct {
 	DB *sql.DB
 }
@@ -13,19 +20,328 @@ func NewExerciseRepo(db *sql.DB) *ExerciseRepo {
 	return &ExerciseRepo{DB: db}
 }
 
-func (repo *ExerciseRepo) ListAllExercises() ([]models.Exercise, error) {
-	rows, err := repo.DB.Query(`SELECT id, name, category_id, muscle_group_id FROM exercises`)
+func isUniqueViolation(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	return false
+}
+
+const exerciseSelect = `
+	SELECT e.id, e.owner_user_id, e.name, e.category_id, c.name, e.muscle_group_id, mg.name, e.metric_type, e.load_type, e.created_at,
+		(
+			SELECT GROUP_CONCAT(emg.muscle_group_id)
+			FROM exercise_muscle_groups emg
+			WHERE emg.exercise_id = e.id
+		) AS secondary_muscle_group_ids
+	FROM exercises e
+	JOIN categories c ON c.id = e.category_id
+	LEFT JOIN muscle_groups mg ON mg.id = e.muscle_group_id
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanExercise(row rowScanner) (models.Exercise, error) {
+	var e models.Exercise
+	var secondary sql.NullString
+	err := row.Scan(&e.Id, &e.OwnerUserId, &e.Name, &e.CategoryId, &e.CategoryName, &e.MuscleGroupId, &e.MuscleGroupName, &e.MetricType, &e.LoadType, &e.CreatedAt, &secondary)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+
+	e.SecondaryMuscleGroupIds = []int64{}
+	if secondary.Valid && secondary.String != "" {
+		for _, raw := range strings.Split(secondary.String, ",") {
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return models.Exercise{}, err
+			}
+			e.SecondaryMuscleGroupIds = append(e.SecondaryMuscleGroupIds, id)
+		}
+	}
+	return e, nil
+}
+
+var exerciseSortColumns = map[string]string{
+	"name":      "e.name",
+	"createdAt": "e.created_at",
+	"category":  "c.name",
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func exerciseSortKey(sort string, e models.Exercise) any {
+	switch sort {
+	case "createdAt":
+		return e.CreatedAt
+	case "category":
+		return e.CategoryName
+	}
+	return e.Name
+}
+
+func (repo *ExerciseRepo) ListAllExercises(userId int64, filter models.ExerciseFilter) ([]models.Exercise, int, *models.Cursor, error) {
+	var where []string
+	var args []any
+
+	switch filter.Owner {
+	case "global":
+		where = append(where, "e.owner_user_id IS NULL")
+	case "mine":
+		where = append(where, "e.owner_user_id = ?")
+		args = append(args, userId)
+	default:
+		where = append(where, "(e.owner_user_id IS NULL OR e.owner_user_id = ?)")
+		args = append(args, userId)
+	}
+
+	if filter.Query != "" {
+		pattern := escapeLike(filter.Query) + "%"
+		if !filter.Prefix {
+			pattern = "%" + pattern
+		}
+		where = append(where, `e.name LIKE ? ESCAPE '\'`)
+		args = append(args, pattern)
+	}
+	if filter.CategoryId != nil {
+		where = append(where, "e.category_id = ?")
+		args = append(args, *filter.CategoryId)
+	}
+	if filter.MuscleGroupId != nil {
+		where = append(where, "e.muscle_group_id = ?")
+		args = append(args, *filter.MuscleGroupId)
+	}
+
+	var total int
+	if err := repo.DB.QueryRow(`
+		SELECT COUNT(*)
+		FROM exercises e
+		WHERE `+strings.Join(where, " AND "), args...).Scan(&total); err != nil {
+		return nil, 0, nil, err
+	}
+
+	sortColumn, ok := exerciseSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = exerciseSortColumns["name"]
+	}
+	direction := "ASC"
+	if filter.Order == "desc" {
+		direction = "DESC"
+	}
+
+	offset := filter.Offset
+	if filter.After != nil {
+		cond, condArgs := keysetCondition(sortColumn, "e.id", direction, false, filter.After)
+		where = append(where, cond)
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	query := exerciseSelect + " WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY " + sortColumn + " " + direction + ", e.id " + direction +
+		" LIMIT ? OFFSET ?"
+	rows, err := repo.DB.Query(query, append(args, filter.Limit+1, offset)...)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer rows.Close()
+	out := []models.Exercise{}
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		out = append(out, exercise)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, nil, err
+	}
+
+	var next *models.Cursor
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+		last := out[filter.Limit-1]
+		next = &models.Cursor{Key: exerciseSortKey(filter.Sort, last), Id: last.Id}
+	}
+	return out, total, next, nil
+}
+
+func (repo *ExerciseRepo) GetExerciseById(id int64) (models.Exercise, error) {
+	e, err := scanExercise(repo.DB.QueryRow(exerciseSelect+` WHERE e.id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Exercise{}, ErrNotFound
+		}
+		return models.Exercise{}, err
+	}
+	return e, nil
+}
+
+// ListExercisesByIds returns the exercises with the given ids keyed by id;
+// unknown ids are left out.
+func (repo *ExerciseRepo) ListExercisesByIds(ids []int64) (map[int64]models.Exercise, error) {
+	out := map[int64]models.Exercise{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := repo.DB.Query(exerciseSelect+` WHERE e.id IN (?`+strings.Repeat(", ?", len(ids)-1)+`)`, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var out []models.Exercise
+
 	for rows.Next() {
-		var exercise models.Exercise
-		if err := rows.Scan(&exercise.Id, &exercise.Name, &exercise.CategoryId, &exercise.MuscleGroupId); err != nil {
+		e, err := scanExercise(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, exercise)
+		out[e.Id] = e
 	}
 	return out, rows.Err()
 }
+
+func (repo *ExerciseRepo) GetVisibleExercise(userId, id int64) (models.Exercise, error) {
+	e, err := scanExercise(repo.DB.QueryRow(exerciseSelect+`
+		WHERE e.id = ? AND (e.owner_user_id IS NULL OR e.owner_user_id = ?)
+	`, id, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Exercise{}, ErrNotFound
+		}
+		return models.Exercise{}, err
+	}
+	return e, nil
+}
+
+func (repo *ExerciseRepo) CreateExercise(ownerUserId int64, name string, categoryId int64, muscleGroupId *int64, metricType, loadType string) (models.Exercise, error) {
+	res, err := repo.DB.Exec(`
+		INSERT INTO exercises (owner_user_id, name, category_id, muscle_group_id, metric_type, load_type)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, ownerUserId, name, categoryId, muscleGroupId, metricType, loadType)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Exercise{}, ErrDuplicate
+		}
+		return models.Exercise{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Exercise{}, err
+	}
+
+	return repo.GetExerciseById(id)
+}
+
+func (repo *ExerciseRepo) UpdateExercise(ownerUserId *int64, id int64, name string, categoryId int64, muscleGroupId *int64, metricType, loadType string) (models.Exercise, error) {
+	res, err := repo.DB.Exec(`
+		UPDATE exercises
+		SET name = ?, category_id = ?, muscle_group_id = ?, metric_type = ?, load_type = ?
+		WHERE id = ? AND owner_user_id IS ?
+	`, name, categoryId, muscleGroupId, metricType, loadType, id, ownerUserId)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Exercise{}, ErrDuplicate
+		}
+		return models.Exercise{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if affected == 0 {
+		return models.Exercise{}, ErrNotFound
+	}
+
+	return repo.GetExerciseById(id)
+}
+
+func (repo *ExerciseRepo) DeleteExercise(ownerUserId *int64, id int64) error {
+	res, err := repo.DB.Exec(`
+		DELETE FROM exercises
+		WHERE id = ? AND owner_user_id IS ?
+	`, id, ownerUserId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *ExerciseRepo) SetSecondaryMuscleGroups(id int64, muscleGroupIds []int64) (models.Exercise, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM exercise_muscle_groups WHERE exercise_id = ?`, id); err != nil {
+		return models.Exercise{}, err
+	}
+	for _, muscleGroupId := range muscleGroupIds {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO exercise_muscle_groups (exercise_id, muscle_group_id)
+			VALUES (?, ?)
+		`, id, muscleGroupId); err != nil {
+			return models.Exercise{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Exercise{}, err
+	}
+
+	return repo.GetExerciseById(id)
+}
+
+func (repo *ExerciseRepo) CountReferences(id int64) (int, error) {
+	var count int
+	err := repo.DB.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM workout_exercises WHERE exercise_id = ?) +
+			(SELECT COUNT(*) FROM routine_exercises WHERE exercise_id = ?)
+	`, id, id).Scan(&count)
+	return count, err
+}
+
+func (repo *ExerciseRepo) CategoryExists(id int64) (bool, error) {
+	var tmp int
+	err := repo.DB.QueryRow(`SELECT 1 FROM categories WHERE id = ?`, id).Scan(&tmp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (repo *ExerciseRepo) MuscleGroupExists(id int64) (bool, error) {
+	var tmp int
+	err := repo.DB.QueryRow(`SELECT 1 FROM muscle_groups WHERE id = ?`, id).Scan(&tmp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+	"workout-tracker/internal/models"
+)
+
+type HistoryRepo struct {
+	DB *sql.DB
+}
+
+func NewHistoryRepo(db *sql.DB) *HistoryRepo {
+	return &HistoryRepo{DB: db}
+}
+
+func historyWhere(userId, exerciseId int64, filter models.HistoryFilter) (string, []any) {
+	where := []string{"w.user_id = ?", "we.exercise_id = ?", "w.status <> ?"}
+	args := []any{userId, exerciseId, models.WorkoutAbandoned}
+	if filter.From != nil {
+		where = append(where, "w.performed_at >= ?")
+		args = append(args, filter.From.UTC().Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		where = append(where, "w.performed_at <= ?")
+		args = append(args, filter.To.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (repo *HistoryRepo) ListExerciseHistory(userId, exerciseId int64, filter models.HistoryFilter) ([]models.ExerciseHistoryEntry, int, *models.Cursor, error) {
+	where, args := historyWhere(userId, exerciseId, filter)
+
+	var total int
+	err := repo.DB.QueryRow(`
+		SELECT COUNT(DISTINCT w.id)
+		FROM workouts w
+		JOIN workout_exercises we ON we.workout_id = w.id
+		WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	offset := filter.Offset
+	if filter.After != nil {
+		cond, condArgs := keysetCondition("w.performed_at", "w.id", "DESC", false, filter.After)
+		where += " AND " + cond
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	pageArgs := append(append([]any{}, args...), filter.Limit+1, offset, exerciseId)
+	rows, err := repo.DB.Query(`
+		SELECT w.id, w.performed_at, w.notes, `+setColumns+`
+		FROM (
+			SELECT DISTINCT w.id, w.performed_at
+			FROM workouts w
+			JOIN workout_exercises we ON we.workout_id = w.id
+			WHERE `+where+`
+			ORDER BY w.performed_at DESC, w.id DESC
+			LIMIT ? OFFSET ?
+		) page
+		JOIN workouts w ON w.id = page.id
+		JOIN workout_exercises we ON we.workout_id = w.id AND we.exercise_id = ?
+		JOIN exercises e ON e.id = we.exercise_id
+		LEFT JOIN sets s ON s.workout_exercise_id = we.id
+		ORDER BY w.performed_at DESC, w.id DESC, we.exercise_order ASC, s.set_number ASC
+	`, pageArgs...)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer rows.Close()
+
+	out := []models.ExerciseHistoryEntry{}
+	for rows.Next() {
+		var (
+			entry     models.ExerciseHistoryEntry
+			setId     sql.NullInt64
+			weId      sql.NullInt64
+			setNumber sql.NullInt64
+			setType   sql.NullString
+			s         models.Set
+		)
+		if err := rows.Scan(
+			&entry.WorkoutId, &entry.PerformedAt, &entry.Notes,
+			&setId, &weId, &setNumber, &setType, &s.Reps, &s.Weight, &s.RPE, &s.RIR, &s.Tempo, &s.RestSeconds,
+			&s.DurationSeconds, &s.DistanceMeters, &s.Calories, &s.AvgHeartRate, &s.MaxHeartRate, &s.Load, &s.CompletedAt,
+		); err != nil {
+			return nil, 0, nil, err
+		}
+
+		if len(out) == 0 || out[len(out)-1].WorkoutId != entry.WorkoutId {
+			entry.Sets = []models.Set{}
+			out = append(out, entry)
+		}
+		if !setId.Valid {
+			continue
+		}
+		s.Id = setId.Int64
+		s.WorkoutExerciseId = weId.Int64
+		s.SetNumber = int(setNumber.Int64)
+		s.SetType = setType.String
+		last := &out[len(out)-1]
+		last.Sets = append(last.Sets, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, nil, err
+	}
+
+	var next *models.Cursor
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+		last := out[filter.Limit-1]
+		next = &models.Cursor{Key: last.PerformedAt, Id: last.WorkoutId}
+	}
+	return out, total, next, nil
+}
@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"workout-tracker/internal/models"
+)
+
+type MuscleGroupRepo struct {
+	DB *sql.DB
+}
+
+func NewMuscleGroupRepo(db *sql.DB) *MuscleGroupRepo {
+	return &MuscleGroupRepo{DB: db}
+}
+
+const muscleGroupSelect = `
+	SELECT mg.id, mg.name, (
+		SELECT COUNT(*)
+		FROM exercises e
+		WHERE (e.owner_user_id IS NULL OR e.owner_user_id = ?)
+			AND (
+				e.muscle_group_id = mg.id
+				OR EXISTS (
+					SELECT 1 FROM exercise_muscle_groups emg
+					WHERE emg.exercise_id = e.id AND emg.muscle_group_id = mg.id
+				)
+			)
+	) AS exercise_count
+	FROM muscle_groups mg
+`
+
+func (repo *MuscleGroupRepo) ListMuscleGroups(userId int64) ([]models.MuscleGroup, error) {
+	rows, err := repo.DB.Query(muscleGroupSelect+` ORDER BY mg.name ASC`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.MuscleGroup{}
+	for rows.Next() {
+		var mg models.MuscleGroup
+		if err := rows.Scan(&mg.Id, &mg.Name, &mg.ExerciseCount); err != nil {
+			return nil, err
+		}
+		out = append(out, mg)
+	}
+	return out, rows.Err()
+}
+
+func (repo *MuscleGroupRepo) GetMuscleGroupById(userId, id int64) (models.MuscleGroup, error) {
+	var mg models.MuscleGroup
+	err := repo.DB.QueryRow(muscleGroupSelect+` WHERE mg.id = ?`, userId, id).Scan(&mg.Id, &mg.Name, &mg.ExerciseCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.MuscleGroup{}, ErrNotFound
+		}
+		return models.MuscleGroup{}, err
+	}
+	return mg, nil
+}
+
+func (repo *MuscleGroupRepo) CreateMuscleGroup(userId int64, name string) (models.MuscleGroup, error) {
+	res, err := repo.DB.Exec(`INSERT INTO muscle_groups (name) VALUES (?)`, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.MuscleGroup{}, ErrDuplicate
+		}
+		return models.MuscleGroup{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.MuscleGroup{}, err
+	}
+
+	return repo.GetMuscleGroupById(userId, id)
+}
+
+func (repo *MuscleGroupRepo) RenameMuscleGroup(userId, id int64, name string) (models.MuscleGroup, error) {
+	res, err := repo.DB.Exec(`UPDATE muscle_groups SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.MuscleGroup{}, ErrDuplicate
+		}
+		return models.MuscleGroup{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.MuscleGroup{}, err
+	}
+	if affected == 0 {
+		return models.MuscleGroup{}, ErrNotFound
+	}
+
+	return repo.GetMuscleGroupById(userId, id)
+}
+
+func (repo *MuscleGroupRepo) DeleteMuscleGroup(id int64) error {
+	res, err := repo.DB.Exec(`DELETE FROM muscle_groups WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (repo *MuscleGroupRepo) CountPrimaryExerciseReferences(id int64) (int, error) {
+	var count int
+	err := repo.DB.QueryRow(`SELECT COUNT(*) FROM exercises WHERE muscle_group_id = ?`, id).Scan(&count)
+	return count, err
+}
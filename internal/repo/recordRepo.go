@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"database/sql"
+	"workout-tracker/internal/models"
+)
+
+type RecordRepo struct {
+	DB *sql.DB
+}
+
+func NewRecordRepo(db *sql.DB) *RecordRepo {
+	return &RecordRepo{DB: db}
+}
+
+// ListSetsForExercise returns the sets records are built from: those of
+// workouts that were not abandoned, and only the ticked-off ones of live
+// sessions, so planned sets don't count before they are lifted.
+func (repo *RecordRepo) ListSetsForExercise(userId, exerciseId int64) ([]models.SetHistoryEntry, error) {
+	rows, err := repo.DB.Query(`
+		SELECT s.id, w.id, we.id, w.performed_at, s.set_number, s.reps, s.weight, `+setLoadExpr+`, e.load_type
+		FROM `+setFrom+`
+		WHERE w.user_id = ? AND we.exercise_id = ? AND w.status <> ?
+			AND (w.started_at IS NULL OR s.completed_at IS NOT NULL)`+warmupCondition(true)+`
+		ORDER BY w.performed_at ASC, w.id ASC, we.exercise_order ASC, s.set_number ASC
+	`, userId, exerciseId, models.WorkoutAbandoned)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []models.SetHistoryEntry
+	for rows.Next() {
+		var e models.SetHistoryEntry
+		if err := rows.Scan(&e.SetId, &e.WorkoutId, &e.WorkoutExerciseId, &e.PerformedAt, &e.SetNumber, &e.Reps, &e.Weight, &e.Load, &e.LoadType); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (repo *RecordRepo) ListExerciseIdsForUser(userId int64) ([]int64, error) {
+	rows, err := repo.DB.Query(`
+		SELECT DISTINCT we.exercise_id
+		FROM workout_exercises we
+		JOIN workouts w ON w.id = we.workout_id
+		WHERE w.user_id = ?
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// ListBodyweightExerciseIds returns the exercises with a bodyweight-based
+// load the user logged in workouts performed at or after since; an empty
+// since means all of them.
+func (repo *RecordRepo) ListBodyweightExerciseIds(userId int64, since string) ([]int64, error) {
+	rows, err := repo.DB.Query(`
+		SELECT DISTINCT we.exercise_id
+		FROM workout_exercises we
+		JOIN workouts w ON w.id = we.workout_id
+		JOIN exercises e ON e.id = we.exercise_id
+		WHERE w.user_id = ? AND w.status <> ? AND e.load_type <> ? AND w.performed_at >= ?
+	`, userId, models.WorkoutAbandoned, models.LoadExternal, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (repo *RecordRepo) ListUserIdsForExercise(exerciseId int64) ([]int64, error) {
+	rows, err := repo.DB.Query(`
+		SELECT DISTINCT w.user_id
+		FROM workout_exercises we
+		JOIN workouts w ON w.id = we.workout_id
+		WHERE we.exercise_id = ?
+	`, exerciseId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+const recordSelect = `
+	SELECT pr.id, pr.user_id, pr.exercise_id, e.name, pr.record_type, pr.value, pr.reps, pr.weight, pr.set_id, pr.workout_id, pr.achieved_at
+	FROM personal_records pr
+	JOIN exercises e ON e.id = pr.exercise_id
+`
+
+func (repo *RecordRepo) queryRecords(query string, args ...any) ([]models.PersonalRecord, error) {
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.PersonalRecord{}
+	for rows.Next() {
+		var r models.PersonalRecord
+		if err := rows.Scan(&r.Id, &r.UserId, &r.ExerciseId, &r.ExerciseName, &r.RecordType, &r.Value, &r.Reps, &r.Weight, &r.SetId, &r.WorkoutId, &r.AchievedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (repo *RecordRepo) ListRecords(userId int64) ([]models.PersonalRecord, error) {
+	return repo.queryRecords(recordSelect+`
+		WHERE pr.user_id = ?
+		ORDER BY e.name ASC, pr.record_type ASC, pr.weight ASC
+	`, userId)
+}
+
+func (repo *RecordRepo) ListRecordsForExercise(userId, exerciseId int64) ([]models.PersonalRecord, error) {
+	return repo.queryRecords(recordSelect+`
+		WHERE pr.user_id = ? AND pr.exercise_id = ?
+		ORDER BY pr.record_type ASC, pr.weight ASC
+	`, userId, exerciseId)
+}
+
+func (repo *RecordRepo) ListRecordsForWorkout(userId, workoutId int64) ([]models.PersonalRecord, error) {
+	return repo.queryRecords(recordSelect+`
+		WHERE pr.user_id = ? AND pr.workout_id = ?
+		ORDER BY e.name ASC, pr.record_type ASC, pr.weight ASC
+	`, userId, workoutId)
+}
+
+func (repo *RecordRepo) ReplaceRecords(userId, exerciseId int64, records []models.PersonalRecord) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`
+		DELETE FROM personal_records
+		WHERE user_id = ? AND exercise_id = ?
+	`, userId, exerciseId); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if _, err := tx.Exec(`
+			INSERT INTO personal_records (user_id, exercise_id, record_type, value, reps, weight, set_id, workout_id, achieved_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, userId, exerciseId, r.RecordType, r.Value, r.Reps, r.Weight, r.SetId, r.WorkoutId, r.AchievedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
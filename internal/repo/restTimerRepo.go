@@ -0,0 +1,164 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"workout-tracker/internal/models"
+)
+
+type RestTimerRepo struct {
+	DB *sql.DB
+}
+
+func NewRestTimerRepo(db *sql.DB) *RestTimerRepo {
+	return &RestTimerRepo{DB: db}
+}
+
+func (repo *RestTimerRepo) ListDefaults(userId int64) ([]models.RestDefault, error) {
+	rows, err := repo.DB.Query(`
+		SELECT exercise_id, rest_seconds
+		FROM exercise_rest_defaults
+		WHERE user_id = ?
+		ORDER BY exercise_id ASC
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.RestDefault{}
+	for rows.Next() {
+		var d models.RestDefault
+		if err := rows.Scan(&d.ExerciseId, &d.RestSeconds); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (repo *RestTimerRepo) GetDefault(userId, exerciseId int64) (models.RestDefault, error) {
+	d := models.RestDefault{ExerciseId: exerciseId}
+	err := repo.DB.QueryRow(`
+		SELECT rest_seconds
+		FROM exercise_rest_defaults
+		WHERE user_id = ? AND exercise_id = ?
+	`, userId, exerciseId).Scan(&d.RestSeconds)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RestDefault{}, ErrNotFound
+		}
+		return models.RestDefault{}, err
+	}
+	return d, nil
+}
+
+func (repo *RestTimerRepo) SetDefault(userId, exerciseId int64, restSeconds int) (models.RestDefault, error) {
+	_, err := repo.DB.Exec(`
+		INSERT INTO exercise_rest_defaults (user_id, exercise_id, rest_seconds)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, exercise_id) DO UPDATE SET rest_seconds = excluded.rest_seconds
+	`, userId, exerciseId, restSeconds)
+	if err != nil {
+		return models.RestDefault{}, err
+	}
+	return models.RestDefault{ExerciseId: exerciseId, RestSeconds: restSeconds}, nil
+}
+
+func (repo *RestTimerRepo) DeleteDefault(userId, exerciseId int64) error {
+	res, err := repo.DB.Exec(`
+		DELETE FROM exercise_rest_defaults
+		WHERE user_id = ? AND exercise_id = ?
+	`, userId, exerciseId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const restTimerColumns = `user_id, workout_id, set_id, duration_seconds, started_at, ends_at`
+
+func scanRestTimer(row rowScanner) (int64, models.RestTimer, error) {
+	var userId int64
+	var t models.RestTimer
+	err := row.Scan(&userId, &t.WorkoutId, &t.SetId, &t.DurationSeconds, &t.StartedAt, &t.EndsAt)
+	return userId, t, err
+}
+
+// Start replaces whatever timer the user had running.
+func (repo *RestTimerRepo) Start(userId int64, t models.RestTimer) error {
+	_, err := repo.DB.Exec(`
+		INSERT INTO rest_timers (`+restTimerColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			workout_id = excluded.workout_id,
+			set_id = excluded.set_id,
+			duration_seconds = excluded.duration_seconds,
+			started_at = excluded.started_at,
+			ends_at = excluded.ends_at
+	`, userId, t.WorkoutId, t.SetId, t.DurationSeconds, t.StartedAt, t.EndsAt)
+	return err
+}
+
+func (repo *RestTimerRepo) Get(userId int64) (models.RestTimer, error) {
+	_, t, err := scanRestTimer(repo.DB.QueryRow(`
+		SELECT `+restTimerColumns+`
+		FROM rest_timers
+		WHERE user_id = ?
+	`, userId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RestTimer{}, ErrNotFound
+		}
+		return models.RestTimer{}, err
+	}
+	return t, nil
+}
+
+// ListAll returns every stored timer keyed by user.
+func (repo *RestTimerRepo) ListAll() (map[int64]models.RestTimer, error) {
+	rows, err := repo.DB.Query(`SELECT ` + restTimerColumns + ` FROM rest_timers`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64]models.RestTimer{}
+	for rows.Next() {
+		userId, t, err := scanRestTimer(rows)
+		if err != nil {
+			return nil, err
+		}
+		out[userId] = t
+	}
+	return out, rows.Err()
+}
+
+// Delete removes the user's timer if it is still the one described by
+// setId and startedAt; ErrNotFound means it was already replaced or gone.
+func (repo *RestTimerRepo) Delete(userId, setId int64, startedAt string) error {
+	res, err := repo.DB.Exec(`
+		DELETE FROM rest_timers
+		WHERE user_id = ? AND set_id = ? AND started_at = ?
+	`, userId, setId, startedAt)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
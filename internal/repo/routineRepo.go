@@ -0,0 +1,278 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"workout-tracker/internal/models"
+)
+
+type RoutineRepo struct {
+	DB *sql.DB
+}
+
+func NewRoutineRepo(db *sql.DB) *RoutineRepo {
+	return &RoutineRepo{DB: db}
+}
+
+func (repo *RoutineRepo) CreateRoutine(userId int64, routine models.Routine) (models.Routine, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return models.Routine{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		INSERT INTO routines (user_id, name, notes)
+		VALUES (?, ?, ?)
+	`, userId, routine.Name, routine.Notes)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Routine{}, ErrDuplicate
+		}
+		return models.Routine{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.Routine{}, err
+	}
+
+	if err := insertRoutineGroupsTx(tx, id, routine.Groups); err != nil {
+		return models.Routine{}, err
+	}
+	if err := insertRoutineExercisesTx(tx, id, routine.Exercises); err != nil {
+		return models.Routine{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Routine{}, err
+	}
+
+	return repo.GetRoutineById(userId, id)
+}
+
+func insertRoutineGroupsTx(tx *sql.Tx, routineId int64, groups []models.ExerciseGroup) error {
+	for _, g := range groups {
+		if _, err := tx.Exec(`
+			INSERT INTO routine_groups (routine_id, label, group_type, rounds, rest_seconds)
+			VALUES (?, ?, ?, ?, ?)
+		`, routineId, g.Label, g.GroupType, g.Rounds, g.RestSeconds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertRoutineExercisesTx(tx *sql.Tx, routineId int64, exercises []models.RoutineExercise) error {
+	for _, ex := range exercises {
+		res, err := tx.Exec(`
+			INSERT INTO routine_exercises (routine_id, exercise_id, exercise_order, notes, group_id)
+			VALUES (?, ?, ?, ?, (SELECT id FROM routine_groups WHERE routine_id = ? AND label = ?))
+		`, routineId, ex.ExerciseId, ex.ExerciseOrder, ex.Notes, routineId, ex.GroupLabel)
+		if err != nil {
+			return err
+		}
+
+		routineExerciseId, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, set := range ex.Sets {
+			if _, err := tx.Exec(`
+				INSERT INTO routine_sets (routine_exercise_id, set_number, reps, weight)
+				VALUES (?, ?, ?, ?)
+			`, routineExerciseId, set.SetNumber, set.Reps, set.Weight); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (repo *RoutineRepo) GetRoutineById(userId, routineId int64) (models.Routine, error) {
+	var r models.Routine
+	err := repo.DB.QueryRow(`
+		SELECT id, user_id, name, notes, created_at, updated_at
+		FROM routines
+		WHERE id = ? AND user_id = ?
+	`, routineId, userId).Scan(&r.Id, &r.UserId, &r.Name, &r.Notes, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Routine{}, ErrNotFound
+		}
+		return models.Routine{}, err
+	}
+
+	if r.Groups, err = listRoutineGroups(repo.DB, routineId); err != nil {
+		return models.Routine{}, err
+	}
+	exercises, err := repo.listRoutineExercises(routineId)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	r.Exercises = exercises
+	return r, nil
+}
+
+func (repo *RoutineRepo) listRoutineExercises(routineId int64) ([]models.RoutineExercise, error) {
+	rows, err := repo.DB.Query(`
+		SELECT re.id, re.routine_id, re.exercise_id, e.name, re.exercise_order, re.notes, g.label
+		FROM routine_exercises re
+		JOIN exercises e ON e.id = re.exercise_id
+		LEFT JOIN routine_groups g ON g.id = re.group_id
+		WHERE re.routine_id = ?
+		ORDER BY re.exercise_order ASC, re.id ASC
+	`, routineId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.RoutineExercise{}
+	for rows.Next() {
+		var re models.RoutineExercise
+		if err := rows.Scan(&re.Id, &re.RoutineId, &re.ExerciseId, &re.ExerciseName, &re.ExerciseOrder, &re.Notes, &re.GroupLabel); err != nil {
+			return nil, err
+		}
+		out = append(out, re)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range out {
+		sets, err := repo.listRoutineSets(out[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		out[i].Sets = sets
+	}
+	return out, nil
+}
+
+func (repo *RoutineRepo) listRoutineSets(routineExerciseId int64) ([]models.RoutineSet, error) {
+	rows, err := repo.DB.Query(`
+		SELECT id, routine_exercise_id, set_number, reps, weight
+		FROM routine_sets
+		WHERE routine_exercise_id = ?
+		ORDER BY set_number ASC
+	`, routineExerciseId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.RoutineSet{}
+	for rows.Next() {
+		var s models.RoutineSet
+		if err := rows.Scan(&s.Id, &s.RoutineExerciseId, &s.SetNumber, &s.Reps, &s.Weight); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (repo *RoutineRepo) ListRoutines(userId int64) ([]models.Routine, error) {
+	rows, err := repo.DB.Query(`
+		SELECT id, user_id, name, notes, created_at, updated_at
+		FROM routines
+		WHERE user_id = ?
+		ORDER BY name ASC, id ASC
+	`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []models.Routine{}
+	for rows.Next() {
+		var r models.Routine
+		if err := rows.Scan(&r.Id, &r.UserId, &r.Name, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range out {
+		if out[i].Groups, err = listRoutineGroups(repo.DB, out[i].Id); err != nil {
+			return nil, err
+		}
+		exercises, err := repo.listRoutineExercises(out[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		out[i].Exercises = exercises
+	}
+	return out, nil
+}
+
+func (repo *RoutineRepo) UpdateRoutine(userId, routineId int64, routine models.Routine) (models.Routine, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return models.Routine{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		UPDATE routines
+		SET name = ?, notes = ?, updated_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now')
+		WHERE id = ? AND user_id = ?
+	`, routine.Name, routine.Notes, routineId, userId)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Routine{}, ErrDuplicate
+		}
+		return models.Routine{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.Routine{}, err
+	}
+	if affected == 0 {
+		return models.Routine{}, ErrNotFound
+	}
+
+	if _, err := tx.Exec(`DELETE FROM routine_exercises WHERE routine_id = ?`, routineId); err != nil {
+		return models.Routine{}, err
+	}
+	if _, err := tx.Exec(`DELETE FROM routine_groups WHERE routine_id = ?`, routineId); err != nil {
+		return models.Routine{}, err
+	}
+	if err := insertRoutineGroupsTx(tx, routineId, routine.Groups); err != nil {
+		return models.Routine{}, err
+	}
+	if err := insertRoutineExercisesTx(tx, routineId, routine.Exercises); err != nil {
+		return models.Routine{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Routine{}, err
+	}
+
+	return repo.GetRoutineById(userId, routineId)
+}
+
+func (repo *RoutineRepo) DeleteRoutine(userId, routineId int64) error {
+	res, err := repo.DB.Exec(`
+		DELETE FROM routines
+		WHERE id = ? AND user_id = ?
+	`, routineId, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -14,12 +14,48 @@ func NewSetRepo(db *sql.DB) *SetRepo {
 	return &SetRepo{DB: db}
 }
 
-func (repo *SetRepo) Create(workoutExerciseId int64, setNumber int, reps *int, weight *float64) (models.Set, error) {
+const setColumns = `s.id, s.workout_exercise_id, s.set_number, s.set_type, s.reps, s.weight, s.rpe, s.rir, s.tempo, s.rest_seconds,
+	s.duration_seconds, s.distance_meters, s.calories, s.avg_heart_rate, s.max_heart_rate, ` + setLoadExpr + `, s.completed_at`
+
+// setFrom joins the workout and exercise every setColumns query needs.
+const setFrom = `sets s
+	JOIN workout_exercises we ON we.id = s.workout_exercise_id
+	JOIN workouts w ON w.id = we.workout_id
+	JOIN exercises e ON e.id = we.exercise_id`
+
+// warmupCondition returns an extra join/where clause on the sets alias s
+// that drops warm-up sets when requested.
+func warmupCondition(excludeWarmups bool) string {
+	if !excludeWarmups {
+		return ""
+	}
+	return " AND s.set_type != '" + models.SetTypeWarmup + "'"
+}
+
+func scanSet(row rowScanner) (models.Set, error) {
+	var s models.Set
+	err := row.Scan(
+		&s.Id, &s.WorkoutExerciseId, &s.SetNumber, &s.SetType, &s.Reps, &s.Weight, &s.RPE, &s.RIR, &s.Tempo, &s.RestSeconds,
+		&s.DurationSeconds, &s.DistanceMeters, &s.Calories, &s.AvgHeartRate, &s.MaxHeartRate, &s.Load, &s.CompletedAt,
+	)
+	return s, err
+}
+
+func (repo *SetRepo) Create(workoutExerciseId int64, set models.Set) (models.Set, error) {
 	res, err := repo.DB.Exec(`
-		INSERT INTO sets (workout_exercise_id, set_number, reps, weight)
-		VALUES (?, ?, ?, ?)
-	`, workoutExerciseId, setNumber, reps, weight)
+		INSERT INTO sets (
+			workout_exercise_id, set_number, set_type, reps, weight, rpe, rir, tempo, rest_seconds,
+			duration_seconds, distance_meters, calories, avg_heart_rate, max_heart_rate
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		workoutExerciseId, set.SetNumber, set.SetType, set.Reps, set.Weight, set.RPE, set.RIR, set.Tempo, set.RestSeconds,
+		set.DurationSeconds, set.DistanceMeters, set.Calories, set.AvgHeartRate, set.MaxHeartRate,
+	)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.Set{}, ErrDuplicate
+		}
 		return models.Set{}, err
 	}
 
@@ -31,13 +67,22 @@ func (repo *SetRepo) Create(workoutExerciseId int64, setNumber int, reps *int, w
 	return repo.GetById(id)
 }
 
-func (repo *SetRepo) GetById(id int64) (models.Set, error) {
-	var s models.Set
+func (repo *SetRepo) NextSetNumber(workoutExerciseId int64) (int, error) {
+	var next int
 	err := repo.DB.QueryRow(`
-		SELECT id, workout_exercise_id, set_number, reps, weight
+		SELECT COALESCE(MAX(set_number), 0) + 1
 		FROM sets
-		WHERE id = ?
-	`, id).Scan(&s.Id, &s.WorkoutExerciseId, &s.SetNumber, &s.Reps, &s.Weight)
+		WHERE workout_exercise_id = ?
+	`, workoutExerciseId).Scan(&next)
+	return next, err
+}
+
+func (repo *SetRepo) GetById(id int64) (models.Set, error) {
+	s, err := scanSet(repo.DB.QueryRow(`
+		SELECT `+setColumns+`
+		FROM `+setFrom+`
+		WHERE s.id = ?
+	`, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Set{}, ErrNotFound
@@ -49,10 +94,10 @@ func (repo *SetRepo) GetById(id int64) (models.Set, error) {
 
 func (repo *SetRepo) ListByWorkoutExercise(workoutExerciseId int64) ([]models.Set, error) {
 	rows, err := repo.DB.Query(`
-		SELECT id, workout_exercise_id, set_number, reps, weight
-		FROM sets
-		WHERE workout_exercise_id = ?
-		ORDER BY set_number ASC
+		SELECT `+setColumns+`
+		FROM `+setFrom+`
+		WHERE s.workout_exercise_id = ?
+		ORDER BY s.set_number ASC
 	`, workoutExerciseId)
 	if err != nil {
 		return nil, err
@@ -61,8 +106,8 @@ func (repo *SetRepo) ListByWorkoutExercise(workoutExerciseId int64) ([]models.Se
 
 	var out []models.Set
 	for rows.Next() {
-		var s models.Set
-		if err := rows.Scan(&s.Id, &s.WorkoutExerciseId, &s.SetNumber, &s.Reps, &s.Weight); err != nil {
+		s, err := scanSet(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, s)
@@ -70,12 +115,17 @@ func (repo *SetRepo) ListByWorkoutExercise(workoutExerciseId int64) ([]models.Se
 	return out, rows.Err()
 }
 
-func (repo *SetRepo) Update(id int64, reps *int, weight *float64) (models.Set, error) {
+func (repo *SetRepo) Update(id int64, set models.Set) (models.Set, error) {
 	res, err := repo.DB.Exec(`
 		UPDATE sets
-		SET reps = ?, weight = ?
+		SET set_type = ?, reps = ?, weight = ?, rpe = ?, rir = ?, tempo = ?, rest_seconds = ?,
+			duration_seconds = ?, distance_meters = ?, calories = ?, avg_heart_rate = ?, max_heart_rate = ?
 		WHERE id = ?
-	`, reps, weight, id)
+	`,
+		set.SetType, set.Reps, set.Weight, set.RPE, set.RIR, set.Tempo, set.RestSeconds,
+		set.DurationSeconds, set.DistanceMeters, set.Calories, set.AvgHeartRate, set.MaxHeartRate,
+		id,
+	)
 	if err != nil {
 		return models.Set{}, err
 	}
@@ -91,18 +141,61 @@ func (repo *SetRepo) Update(id int64, reps *int, weight *float64) (models.Set, e
 	return repo.GetById(id)
 }
 
+func (repo *SetRepo) SetCompletedAt(id int64, completedAt *string) (models.Set, error) {
+	res, err := repo.DB.Exec(`UPDATE sets SET completed_at = ? WHERE id = ?`, completedAt, id)
+	if err != nil {
+		return models.Set{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.Set{}, err
+	}
+	if affected == 0 {
+		return models.Set{}, ErrNotFound
+	}
+
+	return repo.GetById(id)
+}
+
+// Delete removes the set and closes the gap by moving the later sets of the
+// same exercise down by one.
 func (repo *SetRepo) Delete(id int64) error {
-	res, err := repo.DB.Exec(`DELETE FROM sets WHERE id = ?`, id)
+	tx, err := repo.DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer func() { _ = tx.Rollback() }()
 
-	affected, err := res.RowsAffected()
+	var workoutExerciseId int64
+	var setNumber int
+	err = tx.QueryRow(`SELECT workout_exercise_id, set_number FROM sets WHERE id = ?`, id).Scan(&workoutExerciseId, &setNumber)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
 		return err
 	}
-	if affected == 0 {
-		return ErrNotFound
+
+	if _, err := tx.Exec(`DELETE FROM sets WHERE id = ?`, id); err != nil {
+		return err
 	}
-	return nil
+
+	// negate first so the shifted numbers don't hit the unique constraint
+	if _, err := tx.Exec(`
+		UPDATE sets
+		SET set_number = -set_number
+		WHERE workout_exercise_id = ? AND set_number > ?
+	`, workoutExerciseId, setNumber); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE sets
+		SET set_number = -set_number - 1
+		WHERE workout_exercise_id = ? AND set_number < 0
+	`, workoutExerciseId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
@@ -24,6 +24,54 @@ func (repo *UserRepo) GetUserByName(name string) (models.User, error) {
 	return user, nil
 }
 
+func (repo *UserRepo) IsAdmin(userId int64) (bool, error) {
+	var isAdmin bool
+	if err := repo.DB.QueryRow("SELECT is_admin FROM users WHERE id = ?", userId).Scan(&isAdmin); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isAdmin, nil
+}
+
+func (repo *UserRepo) GetPreferences(userId int64) (models.UserPreferences, error) {
+	var prefs models.UserPreferences
+	err := repo.DB.QueryRow(`
+		SELECT e1rm_formula, timezone, weight_unit
+		FROM users
+		WHERE id = ?
+	`, userId).Scan(&prefs.E1RMFormula, &prefs.Timezone, &prefs.WeightUnit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserPreferences{}, ErrNotFound
+		}
+		return models.UserPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (repo *UserRepo) UpdatePreferences(userId int64, prefs models.UserPreferences) (models.UserPreferences, error) {
+	res, err := repo.DB.Exec(`
+		UPDATE users
+		SET e1rm_formula = ?, timezone = ?, weight_unit = ?
+		WHERE id = ?
+	`, prefs.E1RMFormula, prefs.Timezone, prefs.WeightUnit, userId)
+	if err != nil {
+		return models.UserPreferences{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.UserPreferences{}, err
+	}
+	if affected == 0 {
+		return models.UserPreferences{}, ErrNotFound
+	}
+
+	return repo.GetPreferences(userId)
+}
+
 func (repo *UserRepo) InsertUser(name, passwordHash string) (int64, error) {
 	res, err := repo.DB.Exec("INSERT INTO users (name, pass_hash) VALUES(?, ?)", name, passwordHash)
 	if err != nil {
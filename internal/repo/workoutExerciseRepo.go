@@ -14,12 +14,27 @@ func NewWorkoutExerciseRepo(db *sql.DB) *WorkoutExerciseRepo {
 	return &WorkoutExerciseRepo{DB: db}
 }
 
-func (repo *WorkoutExerciseRepo) AddExercise(workoutId, exerciseId int64, exerciseOrder int, notes *string) (models.WorkoutExercise, error) {
+const workoutExerciseSelect = `
+	SELECT we.id, we.workout_id, we.exercise_id, we.exercise_order, we.notes, g.label
+	FROM workout_exercises we
+	LEFT JOIN workout_groups g ON g.id = we.group_id
+`
+
+func scanWorkoutExercise(row rowScanner) (models.WorkoutExercise, error) {
+	var we models.WorkoutExercise
+	err := row.Scan(&we.Id, &we.WorkoutId, &we.ExerciseId, &we.ExerciseOrder, &we.Notes, &we.GroupLabel)
+	return we, err
+}
+
+func (repo *WorkoutExerciseRepo) AddExercise(workoutId, exerciseId int64, exerciseOrder int, notes *string, groupId *int64) (models.WorkoutExercise, error) {
 	res, err := repo.DB.Exec(`
-		INSERT INTO workout_exercises (workout_id, exercise_id, exercise_order, notes)
-		VALUES (?, ?, ?, ?)
-	`, workoutId, exerciseId, exerciseOrder, notes)
+		INSERT INTO workout_exercises (workout_id, exercise_id, exercise_order, notes, group_id)
+		VALUES (?, ?, ?, ?, ?)
+	`, workoutId, exerciseId, exerciseOrder, notes, groupId)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.WorkoutExercise{}, ErrDuplicate
+		}
 		return models.WorkoutExercise{}, err
 	}
 
@@ -31,13 +46,20 @@ func (repo *WorkoutExerciseRepo) AddExercise(workoutId, exerciseId int64, exerci
 	return repo.GetById(id)
 }
 
-func (repo *WorkoutExerciseRepo) GetById(id int64) (models.WorkoutExercise, error) {
-	var we models.WorkoutExercise
+func (repo *WorkoutExerciseRepo) NextExerciseOrder(workoutId int64) (int, error) {
+	var next int
 	err := repo.DB.QueryRow(`
-		SELECT id, workout_id, exercise_id, exercise_order, notes
+		SELECT COALESCE(MAX(exercise_order), 0) + 1
 		FROM workout_exercises
-		WHERE id = ?
-	`, id).Scan(&we.Id, &we.WorkoutId, &we.ExerciseId, &we.ExerciseOrder, &we.Notes)
+		WHERE workout_id = ?
+	`, workoutId).Scan(&next)
+	return next, err
+}
+
+func (repo *WorkoutExerciseRepo) GetById(id int64) (models.WorkoutExercise, error) {
+	we, err := scanWorkoutExercise(repo.DB.QueryRow(workoutExerciseSelect+`
+		WHERE we.id = ?
+	`, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.WorkoutExercise{}, ErrNotFound
@@ -48,21 +70,19 @@ func (repo *WorkoutExerciseRepo) GetById(id int64) (models.WorkoutExercise, erro
 }
 
 func (repo *WorkoutExerciseRepo) ListByWorkout(workoutId int64) ([]models.WorkoutExercise, error) {
-	rows, err := repo.DB.Query(`
-		SELECT id, workout_id, exercise_id, exercise_order, notes
-		FROM workout_exercises
-		WHERE workout_id = ?
-		ORDER BY exercise_order ASC, id ASC
+	rows, err := repo.DB.Query(workoutExerciseSelect+`
+		WHERE we.workout_id = ?
+		ORDER BY we.exercise_order ASC, we.id ASC
 	`, workoutId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var out []models.WorkoutExercise
+	out := []models.WorkoutExercise{}
 	for rows.Next() {
-		var we models.WorkoutExercise
-		if err := rows.Scan(&we.Id, &we.WorkoutId, &we.ExerciseId, &we.ExerciseOrder, &we.Notes); err != nil {
+		we, err := scanWorkoutExercise(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, we)
@@ -70,13 +90,16 @@ func (repo *WorkoutExerciseRepo) ListByWorkout(workoutId int64) ([]models.Workou
 	return out, rows.Err()
 }
 
-func (repo *WorkoutExerciseRepo) Update(id int64, exerciseOrder int, notes *string) (models.WorkoutExercise, error) {
+func (repo *WorkoutExerciseRepo) Update(id int64, exerciseOrder int, notes *string, groupId *int64) (models.WorkoutExercise, error) {
 	res, err := repo.DB.Exec(`
 		UPDATE workout_exercises
-		SET exercise_order = ?, notes = ?
+		SET exercise_order = ?, notes = ?, group_id = ?
 		WHERE id = ?
-	`, exerciseOrder, notes, id)
+	`, exerciseOrder, notes, groupId, id)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return models.WorkoutExercise{}, ErrDuplicate
+		}
 		return models.WorkoutExercise{}, err
 	}
 
@@ -91,6 +114,54 @@ func (repo *WorkoutExerciseRepo) Update(id int64, exerciseOrder int, notes *stri
 	return repo.GetById(id)
 }
 
+// Reorder numbers the workout's exercises 1..n in the given order. check
+// sees the workout's current exercise ids inside the transaction and can
+// reject the order before anything is written.
+func (repo *WorkoutExerciseRepo) Reorder(workoutId int64, ids []int64, check func(current []int64) error) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.Query(`SELECT id FROM workout_exercises WHERE workout_id = ?`, workoutId)
+	if err != nil {
+		return err
+	}
+	var current []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		current = append(current, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := check(current); err != nil {
+		return err
+	}
+
+	// move every exercise out of the way first so the new positions never
+	// meet the old ones on the unique index
+	if _, err := tx.Exec(`UPDATE workout_exercises SET exercise_order = -id WHERE workout_id = ?`, workoutId); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if _, err := tx.Exec(`
+			UPDATE workout_exercises
+			SET exercise_order = ?
+			WHERE id = ? AND workout_id = ?
+		`, i+1, id, workoutId); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (repo *WorkoutExerciseRepo) Delete(id int64) error {
 	res, err := repo.DB.Exec(`DELETE FROM workout_exercises WHERE id = ?`, id)
 	if err != nil {
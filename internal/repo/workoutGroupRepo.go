@@ -0,0 +1,191 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"workout-tracker/internal/models"
+)
+
+type WorkoutGroupRepo struct {
+	DB *sql.DB
+}
+
+func NewWorkoutGroupRepo(db *sql.DB) *WorkoutGroupRepo {
+	return &WorkoutGroupRepo{DB: db}
+}
+
+const groupColumns = `id, label, group_type, rounds, rest_seconds`
+
+func scanGroup(row rowScanner) (models.ExerciseGroup, error) {
+	var g models.ExerciseGroup
+	err := row.Scan(&g.Id, &g.Label, &g.GroupType, &g.Rounds, &g.RestSeconds)
+	return g, err
+}
+
+func scanGroups(rows *sql.Rows) ([]models.ExerciseGroup, error) {
+	defer rows.Close()
+
+	out := []models.ExerciseGroup{}
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+func listWorkoutGroups(q querier, workoutId int64) ([]models.ExerciseGroup, error) {
+	rows, err := q.Query(`
+		SELECT `+groupColumns+`
+		FROM workout_groups
+		WHERE workout_id = ?
+		ORDER BY label ASC
+	`, workoutId)
+	if err != nil {
+		return nil, err
+	}
+	return scanGroups(rows)
+}
+
+func listRoutineGroups(db *sql.DB, routineId int64) ([]models.ExerciseGroup, error) {
+	rows, err := db.Query(`
+		SELECT `+groupColumns+`
+		FROM routine_groups
+		WHERE routine_id = ?
+		ORDER BY label ASC
+	`, routineId)
+	if err != nil {
+		return nil, err
+	}
+	return scanGroups(rows)
+}
+
+// replaceWorkoutGroupsTx makes the workout's groups match the given list,
+// keeping the ids of groups whose label is unchanged.
+func replaceWorkoutGroupsTx(tx *sql.Tx, workoutId int64, groups []models.ExerciseGroup) error {
+	where := "workout_id = ?"
+	args := []any{workoutId}
+	if len(groups) > 0 {
+		where += " AND label NOT IN (?" + strings.Repeat(", ?", len(groups)-1) + ")"
+		for _, g := range groups {
+			args = append(args, g.Label)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM workout_groups WHERE `+where, args...); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		if _, err := tx.Exec(`
+			INSERT INTO workout_groups (workout_id, label, group_type, rounds, rest_seconds)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (workout_id, label) DO UPDATE
+			SET group_type = excluded.group_type, rounds = excluded.rounds, rest_seconds = excluded.rest_seconds
+		`, workoutId, g.Label, g.GroupType, g.Rounds, g.RestSeconds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo *WorkoutGroupRepo) Create(workoutId int64, g models.ExerciseGroup) (models.ExerciseGroup, error) {
+	res, err := repo.DB.Exec(`
+		INSERT INTO workout_groups (workout_id, label, group_type, rounds, rest_seconds)
+		VALUES (?, ?, ?, ?, ?)
+	`, workoutId, g.Label, g.GroupType, g.Rounds, g.RestSeconds)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ExerciseGroup{}, ErrDuplicate
+		}
+		return models.ExerciseGroup{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.ExerciseGroup{}, err
+	}
+
+	group, _, err := repo.GetById(id)
+	return group, err
+}
+
+// GetById returns the group and the workout it belongs to.
+func (repo *WorkoutGroupRepo) GetById(id int64) (models.ExerciseGroup, int64, error) {
+	var workoutId int64
+	g, err := scanGroup(extraScanner{repo.DB.QueryRow(`
+		SELECT `+groupColumns+`, workout_id
+		FROM workout_groups
+		WHERE id = ?
+	`, id), []any{&workoutId}})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ExerciseGroup{}, 0, ErrNotFound
+		}
+		return models.ExerciseGroup{}, 0, err
+	}
+	return g, workoutId, nil
+}
+
+func (repo *WorkoutGroupRepo) GetByLabel(workoutId int64, label string) (models.ExerciseGroup, error) {
+	g, err := scanGroup(repo.DB.QueryRow(`
+		SELECT `+groupColumns+`
+		FROM workout_groups
+		WHERE workout_id = ? AND label = ?
+	`, workoutId, label))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ExerciseGroup{}, ErrNotFound
+		}
+		return models.ExerciseGroup{}, err
+	}
+	return g, nil
+}
+
+func (repo *WorkoutGroupRepo) ListByWorkout(workoutId int64) ([]models.ExerciseGroup, error) {
+	return listWorkoutGroups(repo.DB, workoutId)
+}
+
+func (repo *WorkoutGroupRepo) Update(id int64, g models.ExerciseGroup) (models.ExerciseGroup, error) {
+	res, err := repo.DB.Exec(`
+		UPDATE workout_groups
+		SET label = ?, group_type = ?, rounds = ?, rest_seconds = ?
+		WHERE id = ?
+	`, g.Label, g.GroupType, g.Rounds, g.RestSeconds, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.ExerciseGroup{}, ErrDuplicate
+		}
+		return models.ExerciseGroup{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.ExerciseGroup{}, err
+	}
+	if affected == 0 {
+		return models.ExerciseGroup{}, ErrNotFound
+	}
+
+	group, _, err := repo.GetById(id)
+	return group, err
+}
+
+// Delete removes the group; its exercises stay in the workout ungrouped.
+func (repo *WorkoutGroupRepo) Delete(id int64) error {
+	res, err := repo.DB.Exec(`DELETE FROM workout_groups WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package repo
 import (
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 	"workout-tracker/internal/models"
 )
 
@@ -33,20 +35,265 @@ func (repo *WorkoutRepo) CreateWorkout(userId int64, performedAt string, duratio
 	return repo.GetWorkoutById(userId, id)
 }
 
-func (repo *WorkoutRepo) GetWorkoutById(userId, workoutId int64) (models.Workout, error) {
+const workoutColumns = `w.id, w.user_id, w.performed_at, w.utc_offset, w.timezone, w.duration_minutes, w.notes, w.routine_id,
+	w.status, w.started_at, w.finished_at, w.created_at`
+
+const workoutSelect = `
+	SELECT ` + workoutColumns + `
+	FROM workouts w
+`
+
+const workoutVolumeExpr = `(
+	SELECT COALESCE(SUM(COALESCE(s.reps, 0) * COALESCE(` + setVolumeLoadExpr + `, 0)), 0)
+	FROM workout_exercises we
+	JOIN exercises e ON e.id = we.exercise_id
+	JOIN sets s ON s.workout_exercise_id = we.id
+	WHERE we.workout_id = w.id
+)`
+
+var workoutSortColumns = map[string]string{
+	"date":     "w.performed_at",
+	"duration": "w.duration_minutes",
+	"volume":   workoutVolumeExpr,
+}
+
+func scanWorkout(row rowScanner) (models.Workout, error) {
 	var w models.Workout
-	err := repo.DB.QueryRow(`
-		SELECT id, user_id, performed_at, duration_minutes, notes, created_at
-		FROM workouts
-		WHERE id = ? AND user_id = ?
-	`, workoutId, userId).Scan(
+	err := row.Scan(
 		&w.Id,
 		&w.UserId,
 		&w.PerformedAt,
+		&w.UTCOffset,
+		&w.Timezone,
 		&w.DurationMinutes,
 		&w.Notes,
+		&w.RoutineId,
+		&w.Status,
+		&w.StartedAt,
+		&w.FinishedAt,
 		&w.CreatedAt,
 	)
+	w.PerformedAtLocal = localTime(w.PerformedAt, w.UTCOffset)
+	return w, err
+}
+
+// localTime shifts a UTC timestamp to the offset it was recorded in.
+func localTime(performedAt, offset string) string {
+	t, err := time.Parse(time.RFC3339, performedAt)
+	if err != nil {
+		return performedAt
+	}
+	zone, err := time.Parse("-07:00", offset)
+	if err != nil {
+		return performedAt
+	}
+	_, seconds := zone.Zone()
+	return t.In(time.FixedZone("", seconds)).Format(time.RFC3339)
+}
+
+func (repo *WorkoutRepo) CreateWorkoutWithDetails(userId int64, details models.WorkoutWithDetails) (int64, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	status := details.Status
+	if status == "" {
+		status = models.WorkoutCompleted
+	}
+	res, err := tx.Exec(`
+		INSERT INTO workouts (user_id, performed_at, utc_offset, timezone, duration_minutes, notes, routine_id, status, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, userId, details.PerformedAt, details.UTCOffset, details.Timezone, details.DurationMinutes, details.Notes, details.RoutineId, status, details.StartedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, ErrDuplicate
+		}
+		return 0, err
+	}
+
+	workoutId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := replaceWorkoutGroupsTx(tx, workoutId, details.Groups); err != nil {
+		return 0, err
+	}
+	for _, ex := range details.Exercises {
+		if _, err := insertWorkoutExerciseTx(tx, workoutId, ex); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return workoutId, nil
+}
+
+// ReplaceWorkoutDetails reads the workout's details, lets build turn them
+// into a diff and applies it, all in one transaction, so the diff is never
+// built from a stale read. It returns the details as they were before.
+func (repo *WorkoutRepo) ReplaceWorkoutDetails(userId, workoutId int64, build func(current models.WorkoutWithDetails) (models.WorkoutDetailsDiff, error)) (models.WorkoutWithDetails, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	current, err := getWorkoutDetails(tx, userId, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	diff, err := build(current)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := applyWorkoutDetailsDiffTx(tx, userId, workoutId, diff); err != nil {
+		if isUniqueViolation(err) {
+			return models.WorkoutWithDetails{}, ErrDuplicate
+		}
+		return models.WorkoutWithDetails{}, err
+	}
+	return current, tx.Commit()
+}
+
+func applyWorkoutDetailsDiffTx(tx *sql.Tx, userId, workoutId int64, diff models.WorkoutDetailsDiff) error {
+	res, err := tx.Exec(`
+		UPDATE workouts
+		SET performed_at = ?, utc_offset = ?, timezone = ?, duration_minutes = ?, notes = ?
+		WHERE id = ? AND user_id = ?
+	`, diff.Workout.PerformedAt, diff.Workout.UTCOffset, diff.Workout.Timezone, diff.Workout.DurationMinutes, diff.Workout.Notes, workoutId, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	if diff.Groups != nil {
+		if err := replaceWorkoutGroupsTx(tx, workoutId, diff.Groups); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range diff.DeleteSetIds {
+		if _, err := tx.Exec(`
+			DELETE FROM sets
+			WHERE id = ? AND workout_exercise_id IN (SELECT id FROM workout_exercises WHERE workout_id = ?)
+		`, id, workoutId); err != nil {
+			return err
+		}
+	}
+	for _, id := range diff.DeleteWorkoutExerciseIds {
+		if _, err := tx.Exec(`DELETE FROM workout_exercises WHERE id = ? AND workout_id = ?`, id, workoutId); err != nil {
+			return err
+		}
+	}
+
+	// move updated sets out of the way first so swapped set numbers don't hit the unique constraint
+	for _, set := range diff.UpdateSets {
+		if _, err := tx.Exec(`UPDATE sets SET set_number = -id WHERE id = ?`, set.Id); err != nil {
+			return err
+		}
+	}
+	for _, set := range diff.UpdateSets {
+		if _, err := tx.Exec(`
+			UPDATE sets
+			SET set_number = ?, set_type = ?, reps = ?, weight = ?, rpe = ?, rir = ?, tempo = ?, rest_seconds = ?,
+				duration_seconds = ?, distance_meters = ?, calories = ?, avg_heart_rate = ?, max_heart_rate = ?
+			WHERE id = ? AND workout_exercise_id IN (SELECT id FROM workout_exercises WHERE workout_id = ?)
+		`,
+			set.SetNumber, set.SetType, set.Reps, set.Weight, set.RPE, set.RIR, set.Tempo, set.RestSeconds,
+			set.DurationSeconds, set.DistanceMeters, set.Calories, set.AvgHeartRate, set.MaxHeartRate,
+			set.Id, workoutId,
+		); err != nil {
+			return err
+		}
+	}
+
+	// same for exercises whose positions may have been swapped
+	for _, we := range diff.UpdateWorkoutExercises {
+		if _, err := tx.Exec(`UPDATE workout_exercises SET exercise_order = -id WHERE id = ? AND workout_id = ?`, we.Id, workoutId); err != nil {
+			return err
+		}
+	}
+	for _, we := range diff.UpdateWorkoutExercises {
+		if _, err := tx.Exec(`
+			UPDATE workout_exercises
+			SET exercise_id = ?, exercise_order = ?, notes = ?, group_id = `+groupIdByLabel+`
+			WHERE id = ? AND workout_id = ?
+		`, we.ExerciseId, we.ExerciseOrder, we.Notes, workoutId, we.GroupLabel, we.Id, workoutId); err != nil {
+			return err
+		}
+	}
+
+	for _, set := range diff.InsertSets {
+		if _, err := insertSetTx(tx, set.WorkoutExerciseId, set); err != nil {
+			return err
+		}
+	}
+	for _, ex := range diff.InsertWorkoutExercises {
+		if _, err := insertWorkoutExerciseTx(tx, workoutId, ex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupIdByLabel resolves a workout id and group label to the group's id, or
+// NULL for no label.
+const groupIdByLabel = `(SELECT id FROM workout_groups WHERE workout_id = ? AND label = ?)`
+
+func insertWorkoutExerciseTx(tx *sql.Tx, workoutId int64, ex models.WorkoutExerciseWithSets) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO workout_exercises (workout_id, exercise_id, exercise_order, notes, group_id)
+		VALUES (?, ?, ?, ?, `+groupIdByLabel+`)
+	`, workoutId, ex.ExerciseId, ex.ExerciseOrder, ex.Notes, workoutId, ex.GroupLabel)
+	if err != nil {
+		return 0, err
+	}
+
+	workoutExerciseId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, set := range ex.Sets {
+		if _, err := insertSetTx(tx, workoutExerciseId, set); err != nil {
+			return 0, err
+		}
+	}
+	return workoutExerciseId, nil
+}
+
+func insertSetTx(tx *sql.Tx, workoutExerciseId int64, set models.Set) (int64, error) {
+	res, err := tx.Exec(`
+		INSERT INTO sets (
+			workout_exercise_id, set_number, set_type, reps, weight, rpe, rir, tempo, rest_seconds,
+			duration_seconds, distance_meters, calories, avg_heart_rate, max_heart_rate
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		workoutExerciseId, set.SetNumber, set.SetType, set.Reps, set.Weight, set.RPE, set.RIR, set.Tempo, set.RestSeconds,
+		set.DurationSeconds, set.DistanceMeters, set.Calories, set.AvgHeartRate, set.MaxHeartRate,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (repo *WorkoutRepo) GetWorkoutById(userId, workoutId int64) (models.Workout, error) {
+	w, err := scanWorkout(repo.DB.QueryRow(workoutSelect+`
+		WHERE id = ? AND user_id = ?
+	`, workoutId, userId))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.Workout{}, ErrNotFound
@@ -56,36 +303,123 @@ func (repo *WorkoutRepo) GetWorkoutById(userId, workoutId int64) (models.Workout
 	return w, nil
 }
 
-func (repo *WorkoutRepo) ListWorkouts(userId int64, limit, offset int) ([]models.Workout, error) {
-	rows, err := repo.DB.Query(`
-		SELECT id, user_id, performed_at, duration_minutes, notes, created_at
-		FROM workouts
-		WHERE user_id = ?
-		ORDER BY performed_at DESC
-		LIMIT ? OFFSET ?
-	`, userId, limit, offset)
+func (repo *WorkoutRepo) ListWorkouts(userId int64, filter models.WorkoutFilter) ([]models.Workout, int, *models.Cursor, error) {
+	where := []string{"w.user_id = ?"}
+	args := []any{userId}
+
+	if filter.From != nil {
+		where = append(where, "w.performed_at >= ?")
+		args = append(args, filter.From.UTC().Format(time.RFC3339))
+	}
+	if filter.To != nil {
+		where = append(where, "w.performed_at <= ?")
+		args = append(args, filter.To.UTC().Format(time.RFC3339))
+	}
+	if filter.ExerciseId != nil {
+		where = append(where, `EXISTS (
+			SELECT 1 FROM workout_exercises we
+			WHERE we.workout_id = w.id AND we.exercise_id = ?
+		)`)
+		args = append(args, *filter.ExerciseId)
+	}
+	if filter.CategoryId != nil {
+		where = append(where, `EXISTS (
+			SELECT 1 FROM workout_exercises we
+			JOIN exercises e ON e.id = we.exercise_id
+			WHERE we.workout_id = w.id AND e.category_id = ?
+		)`)
+		args = append(args, *filter.CategoryId)
+	}
+	if filter.MuscleGroupId != nil {
+		where = append(where, `EXISTS (
+			SELECT 1 FROM workout_exercises we
+			JOIN exercises e ON e.id = we.exercise_id
+			WHERE we.workout_id = w.id AND (
+				e.muscle_group_id = ?
+				OR EXISTS (
+					SELECT 1 FROM exercise_muscle_groups emg
+					WHERE emg.exercise_id = e.id AND emg.muscle_group_id = ?
+				)
+			)
+		)`)
+		args = append(args, *filter.MuscleGroupId, *filter.MuscleGroupId)
+	}
+	if filter.Query != "" {
+		where = append(where, `w.notes LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+	}
+	if filter.MinDuration != nil {
+		where = append(where, "w.duration_minutes >= ?")
+		args = append(args, *filter.MinDuration)
+	}
+	if filter.MaxDuration != nil {
+		where = append(where, "w.duration_minutes <= ?")
+		args = append(args, *filter.MaxDuration)
+	}
+	if filter.Status != "" {
+		where = append(where, "w.status = ?")
+		args = append(args, filter.Status)
+	}
+	var total int
+	if err := repo.DB.QueryRow("SELECT COUNT(*) FROM workouts w WHERE "+strings.Join(where, " AND "), args...).Scan(&total); err != nil {
+		return nil, 0, nil, err
+	}
+
+	sortColumn, ok := workoutSortColumns[filter.Sort]
+	if !ok {
+		sortColumn = workoutSortColumns["date"]
+	}
+	direction := "DESC"
+	if filter.Order == "asc" {
+		direction = "ASC"
+	}
+
+	offset := filter.Offset
+	if filter.After != nil {
+		cond, condArgs := keysetCondition(sortColumn, "w.id", direction, filter.Sort == "duration", filter.After)
+		where = append(where, cond)
+		args = append(args, condArgs...)
+		offset = 0
+	}
+
+	query := "SELECT " + workoutColumns + ", " + sortColumn + " FROM workouts w WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY " + sortColumn + " " + direction + " NULLS LAST, w.id " + direction +
+		" LIMIT ? OFFSET ?"
+	rows, err := repo.DB.Query(query, append(args, filter.Limit+1, offset)...)
 	if err != nil {
-		return nil, err
+		return nil, 0, nil, err
 	}
 	defer rows.Close()
 
-	var out []models.Workout
+	out := []models.Workout{}
+	var keys []any
 	for rows.Next() {
-		var w models.Workout
-		if err := rows.Scan(&w.Id, &w.UserId, &w.PerformedAt, &w.DurationMinutes, &w.Notes, &w.CreatedAt); err != nil {
-			return nil, err
+		var key any
+		w, err := scanWorkout(extraScanner{rows, []any{&key}})
+		if err != nil {
+			return nil, 0, nil, err
 		}
 		out = append(out, w)
+		keys = append(keys, cursorKey(key))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, nil, err
+	}
+
+	var next *models.Cursor
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+		next = &models.Cursor{Key: keys[filter.Limit-1], Id: out[filter.Limit-1].Id}
 	}
-	return out, rows.Err()
+	return out, total, next, nil
 }
 
-func (repo *WorkoutRepo) UpdateWorkout(userId, workoutId int64, performedAt string, durationMinutes *int, notes *string) (models.Workout, error) {
+func (repo *WorkoutRepo) UpdateWorkout(userId, workoutId int64, w models.Workout) (models.Workout, error) {
 	res, err := repo.DB.Exec(`
 		UPDATE workouts
-		SET performed_at = ?, duration_minutes = ?, notes = ?
+		SET performed_at = ?, utc_offset = ?, timezone = ?, duration_minutes = ?, notes = ?
 		WHERE id = ? AND user_id = ?
-	`, performedAt, durationMinutes, notes, workoutId, userId)
+	`, w.PerformedAt, w.UTCOffset, w.Timezone, w.DurationMinutes, w.Notes, workoutId, userId)
 	if err != nil {
 		return models.Workout{}, err
 	}
@@ -120,6 +454,157 @@ func (repo *WorkoutRepo) DeleteWorkout(userId, workoutId int64) error {
 	return nil
 }
 
+// GetInProgress returns the user's live session, if any.
+func (repo *WorkoutRepo) GetInProgress(userId int64) (models.Workout, error) {
+	w, err := scanWorkout(repo.DB.QueryRow(workoutSelect+`
+		WHERE w.user_id = ? AND w.status = ?
+	`, userId, models.WorkoutInProgress))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Workout{}, ErrNotFound
+		}
+		return models.Workout{}, err
+	}
+	return w, nil
+}
+
+// GetLatestByRoutine returns the user's most recent completed workout
+// started from the routine.
+func (repo *WorkoutRepo) GetLatestByRoutine(userId, routineId int64) (models.Workout, error) {
+	w, err := scanWorkout(repo.DB.QueryRow(workoutSelect+`
+		WHERE w.user_id = ? AND w.routine_id = ? AND w.status = ?
+		ORDER BY w.performed_at DESC, w.id DESC
+		LIMIT 1
+	`, userId, routineId, models.WorkoutCompleted))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Workout{}, ErrNotFound
+		}
+		return models.Workout{}, err
+	}
+	return w, nil
+}
+
+// ListPreviousExercises returns, per exercise id, the sets of that exercise
+// from the user's last completed workout before w that has it. Exercises
+// never done before are missing from the map.
+func (repo *WorkoutRepo) ListPreviousExercises(userId int64, w models.Workout, exerciseIds []int64) (map[int64]models.PreviousExercise, error) {
+	out := map[int64]models.PreviousExercise{}
+	if len(exerciseIds) == 0 {
+		return out, nil
+	}
+
+	args := []any{userId, models.WorkoutCompleted, w.Id, w.PerformedAt, w.PerformedAt, w.Id}
+	for _, id := range exerciseIds {
+		args = append(args, id)
+	}
+	rows, err := repo.DB.Query(`
+		SELECT exercise_id, workout_id, performed_at
+		FROM (
+			SELECT we.exercise_id, w.id AS workout_id, w.performed_at,
+				ROW_NUMBER() OVER (PARTITION BY we.exercise_id ORDER BY w.performed_at DESC, w.id DESC) AS rn
+			FROM workouts w
+			JOIN workout_exercises we ON we.workout_id = w.id
+			WHERE w.user_id = ? AND w.status = ? AND w.id != ?
+				AND (w.performed_at < ? OR (w.performed_at = ? AND w.id < ?))
+				AND we.exercise_id IN (?`+strings.Repeat(", ?", len(exerciseIds)-1)+`)
+		)
+		WHERE rn = 1
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []any
+	for rows.Next() {
+		var exerciseId int64
+		prev := models.PreviousExercise{Sets: []models.Set{}}
+		if err := rows.Scan(&exerciseId, &prev.WorkoutId, &prev.PerformedAt); err != nil {
+			return nil, err
+		}
+		out[exerciseId] = prev
+		pairs = append(pairs, prev.WorkoutId, exerciseId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	setRows, err := repo.DB.Query(`
+		SELECT `+setColumns+`, we.exercise_id
+		FROM `+setFrom+`
+		WHERE (we.workout_id, we.exercise_id) IN (VALUES (?, ?)`+strings.Repeat(", (?, ?)", len(out)-1)+`)
+		ORDER BY we.exercise_order ASC, we.id ASC, s.set_number ASC
+	`, pairs...)
+	if err != nil {
+		return nil, err
+	}
+	defer setRows.Close()
+
+	for setRows.Next() {
+		var exerciseId int64
+		s, err := scanSet(extraScanner{setRows, []any{&exerciseId}})
+		if err != nil {
+			return nil, err
+		}
+		prev := out[exerciseId]
+		prev.Sets = append(prev.Sets, s)
+		out[exerciseId] = prev
+	}
+	return out, setRows.Err()
+}
+
+// FinishWorkout completes a live session. It returns ErrNotFound when the
+// workout is not the user's or not in progress.
+func (repo *WorkoutRepo) FinishWorkout(userId, workoutId int64, finishedAt string, durationMinutes int) (models.Workout, error) {
+	res, err := repo.DB.Exec(`
+		UPDATE workouts
+		SET status = ?, finished_at = ?, duration_minutes = ?
+		WHERE id = ? AND user_id = ? AND status = ?
+	`, models.WorkoutCompleted, finishedAt, durationMinutes, workoutId, userId, models.WorkoutInProgress)
+	if err != nil {
+		return models.Workout{}, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if affected == 0 {
+		return models.Workout{}, ErrNotFound
+	}
+
+	return repo.GetWorkoutById(userId, workoutId)
+}
+
+// AbandonStale marks the user's live session abandoned when its last
+// activity (the start or the latest completed set) is before cutoff and
+// returns its id; ErrNotFound means there was nothing to abandon.
+func (repo *WorkoutRepo) AbandonStale(userId int64, cutoff string) (int64, error) {
+	var workoutId int64
+	err := repo.DB.QueryRow(`
+		UPDATE workouts
+		SET status = ?
+		WHERE user_id = ? AND status = ? AND MAX(started_at, COALESCE((
+			SELECT MAX(s.completed_at)
+			FROM sets s
+			JOIN workout_exercises we ON we.id = s.workout_exercise_id
+			WHERE we.workout_id = workouts.id
+		), started_at)) < ?
+		RETURNING id
+	`, models.WorkoutAbandoned, userId, models.WorkoutInProgress, cutoff).Scan(&workoutId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return workoutId, nil
+}
+
 func (repo *WorkoutRepo) MustBeWorkoutOwner(userId, workoutId int64) error {
 	var tmp int
 	err := repo.DB.QueryRow(`
@@ -137,75 +622,103 @@ func (repo *WorkoutRepo) MustBeWorkoutOwner(userId, workoutId int64) error {
 }
 
 func (repo *WorkoutRepo) GetWorkoutDetails(userId, workoutId int64) (models.WorkoutWithDetails, error) {
-	workout, err := repo.GetWorkoutById(userId, workoutId)
+	return getWorkoutDetails(repo.DB, userId, workoutId)
+}
+
+// querier is what reading details needs from either the pool or a
+// transaction.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func getWorkoutDetails(q querier, userId, workoutId int64) (models.WorkoutWithDetails, error) {
+	workout, err := scanWorkout(q.QueryRow(workoutSelect+`
+		WHERE id = ? AND user_id = ?
+	`, workoutId, userId))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.WorkoutWithDetails{}, ErrNotFound
+		}
 		return models.WorkoutWithDetails{}, err
 	}
 
-	exRows, err := repo.DB.Query(`
-		SELECT id, workout_id, exercise_id, exercise_order, notes
-		FROM workout_exercises
-		WHERE workout_id = ?
-		ORDER BY exercise_order ASC, id ASC
+	groups, err := listWorkoutGroups(q, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	exRows, err := q.Query(workoutExerciseSelect+`
+		WHERE we.workout_id = ?
+		ORDER BY we.exercise_order ASC, we.id ASC
 	`, workoutId)
 	if err != nil {
 		return models.WorkoutWithDetails{}, err
 	}
 	defer exRows.Close()
 
-	var exercises []models.WorkoutExerciseWithSets
+	exercises := []models.WorkoutExerciseWithSets{}
+	index := map[int64]int{}
 	for exRows.Next() {
-		var we models.WorkoutExercise
-		if err := exRows.Scan(&we.Id, &we.WorkoutId, &we.ExerciseId, &we.ExerciseOrder, &we.Notes); err != nil {
+		we, err := scanWorkoutExercise(exRows)
+		if err != nil {
 			return models.WorkoutWithDetails{}, err
 		}
 
-		sets, err := repo.listSetsByWorkoutExerciseId(we.Id)
+		ex := models.WorkoutExerciseWithSets{WorkoutExercise: we}
+		if we.GroupLabel != nil {
+			for i := range groups {
+				if groups[i].Label == *we.GroupLabel {
+					ex.Group = &groups[i]
+				}
+			}
+		}
+		index[we.Id] = len(exercises)
+		exercises = append(exercises, ex)
+	}
+	if err := exRows.Err(); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	exRows.Close()
+
+	setRows, err := q.Query(`
+		SELECT `+setColumns+`
+		FROM `+setFrom+`
+		WHERE we.workout_id = ?
+		ORDER BY s.set_number ASC
+	`, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	defer setRows.Close()
+
+	for setRows.Next() {
+		s, err := scanSet(setRows)
 		if err != nil {
 			return models.WorkoutWithDetails{}, err
 		}
-
-		exercises = append(exercises, models.WorkoutExerciseWithSets{
-			WorkoutExercise: we,
-			Sets:            sets,
-		})
+		ex := &exercises[index[s.WorkoutExerciseId]]
+		ex.Sets = append(ex.Sets, s)
 	}
-	if err := exRows.Err(); err != nil {
+	if err := setRows.Err(); err != nil {
 		return models.WorkoutWithDetails{}, err
 	}
 
 	return models.WorkoutWithDetails{
 		Workout:   workout,
+		Groups:    groups,
 		Exercises: exercises,
 	}, nil
 }
 
-func (repo *WorkoutRepo) listSetsByWorkoutExerciseId(workoutExerciseId int64) ([]models.Set, error) {
-	rows, err := repo.DB.Query(`
-		SELECT id, workout_exercise_id, set_number, reps, weight
-		FROM sets
-		WHERE workout_exercise_id = ?
-		ORDER BY set_number ASC
-	`, workoutExerciseId)
+func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64, excludeWarmups bool) (models.WorkoutReport, error) {
+	w, err := repo.GetWorkoutById(userId, workoutId)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var out []models.Set
-	for rows.Next() {
-		var s models.Set
-		if err := rows.Scan(&s.Id, &s.WorkoutExerciseId, &s.SetNumber, &s.Reps, &s.Weight); err != nil {
-			return nil, err
-		}
-		out = append(out, s)
+		return models.WorkoutReport{}, err
 	}
-	return out, rows.Err()
-}
 
-func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64) (models.WorkoutReport, error) {
-	w, err := repo.GetWorkoutById(userId, workoutId)
-	if err != nil {
+	var bodyweight *float64
+	if err := repo.DB.QueryRow(`SELECT `+bodyweightAt+` FROM workouts w WHERE w.id = ?`, workoutId).Scan(&bodyweight); err != nil {
 		return models.WorkoutReport{}, err
 	}
 
@@ -213,15 +726,26 @@ func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64) (models.Worko
 		SELECT
 			e.id AS exercise_id,
 			e.name AS exercise_name,
+			e.metric_type,
+			e.load_type,
 			COUNT(s.id) AS sets_count,
 			COALESCE(SUM(COALESCE(s.reps, 0)), 0) AS total_reps,
 			MAX(s.weight) AS max_weight,
-			COALESCE(SUM(COALESCE(s.reps, 0) * COALESCE(s.weight, 0)), 0) AS total_volume
+			COALESCE(SUM(COALESCE(s.reps, 0) * COALESCE(`+setVolumeLoadExpr+`, 0)), 0) AS total_volume,
+			ROUND(AVG(s.rpe), 2) AS avg_rpe,
+			COALESCE(SUM(s.duration_seconds), 0) AS total_duration,
+			COALESCE(SUM(s.distance_meters), 0) AS total_distance,
+			ROUND(
+				SUM(CASE WHEN s.distance_meters > 0 THEN s.duration_seconds END) * 1000.0 /
+				SUM(CASE WHEN s.duration_seconds IS NOT NULL THEN s.distance_meters END),
+				1
+			) AS pace
 		FROM workout_exercises we
+		JOIN workouts w ON w.id = we.workout_id
 		JOIN exercises e ON e.id = we.exercise_id
-		LEFT JOIN sets s ON s.workout_exercise_id = we.id
+		LEFT JOIN sets s ON s.workout_exercise_id = we.id`+warmupCondition(excludeWarmups)+`
 		WHERE we.workout_id = ?
-		GROUP BY e.id, e.name
+		GROUP BY e.id, e.name, e.metric_type, e.load_type
 		ORDER BY MIN(we.exercise_order) ASC, e.name ASC
 	`, workoutId)
 	if err != nil {
@@ -230,6 +754,7 @@ func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64) (models.Worko
 	defer rows.Close()
 
 	report := models.WorkoutReport{
+		Bodyweight:      bodyweight,
 		WorkoutId:       w.Id,
 		UserId:          w.UserId,
 		PerformedAt:     w.PerformedAt,
@@ -243,10 +768,16 @@ func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64) (models.Worko
 		if err := rows.Scan(
 			&ex.ExerciseId,
 			&ex.ExerciseName,
+			&ex.MetricType,
+			&ex.LoadType,
 			&ex.SetsCount,
 			&ex.TotalReps,
 			&ex.MaxWeight,
 			&ex.TotalVolume,
+			&ex.AvgRPE,
+			&ex.TotalDurationSeconds,
+			&ex.TotalDistanceMeters,
+			&ex.PaceSecondsPerKm,
 		); err != nil {
 			return models.WorkoutReport{}, err
 		}
@@ -255,6 +786,8 @@ func (repo *WorkoutRepo) GetWorkoutReport(userId, workoutId int64) (models.Worko
 		report.TotalSets += ex.SetsCount
 		report.TotalReps += ex.TotalReps
 		report.TotalVolume += ex.TotalVolume
+		report.TotalDurationSeconds += ex.TotalDurationSeconds
+		report.TotalDistanceMeters += ex.TotalDistanceMeters
 	}
 
 	if err := rows.Err(); err != nil {
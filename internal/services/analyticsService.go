@@ -0,0 +1,412 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+const maxAnalyticsBuckets = 1000
+
+type AnalyticsService struct {
+	AnalyticsRepo *repo.AnalyticsRepo
+	UserRepo      *repo.UserRepo
+}
+
+func NewAnalyticsService(ar *repo.AnalyticsRepo, ur *repo.UserRepo) *AnalyticsService {
+	return &AnalyticsService{
+		AnalyticsRepo: ar,
+		UserRepo:      ur,
+	}
+}
+
+// resolveRange parses from/to in loc. to defaults to now and from to
+// whatever back returns for it.
+func resolveRange(fromRaw, toRaw string, loc *time.Location, back func(time.Time) time.Time) (time.Time, time.Time, error) {
+	from, err := parseRangeBound("from", fromRaw, loc, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := parseRangeBound("to", toRaw, loc, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to == nil {
+		now := time.Now().In(loc)
+		to = &now
+	}
+	if from == nil {
+		f := back(*to)
+		from = &f
+	}
+	if from.After(*to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+	return *from, *to, nil
+}
+
+type bucketer struct {
+	start func(time.Time) time.Time
+	next  func(time.Time) time.Time
+	label func(time.Time) string
+}
+
+func newBucketer(period string, days int, origin time.Time) (bucketer, error) {
+	switch period {
+	case "day":
+		return bucketer{
+			start: startOfDay,
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+			label: func(t time.Time) string { return t.Format(time.DateOnly) },
+		}, nil
+	case "", "week":
+		return bucketer{
+			start: startOfISOWeek,
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 0, 7) },
+			label: func(t time.Time) string {
+				y, w := t.ISOWeek()
+				return fmt.Sprintf("%d-W%02d", y, w)
+			},
+		}, nil
+	case "month":
+		return bucketer{
+			start: startOfMonth,
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+			label: func(t time.Time) string { return t.Format("2006-01") },
+		}, nil
+	case "custom":
+		if days <= 0 || days > 366 {
+			return bucketer{}, fmt.Errorf("%w: days must be between 1 and 366 for a custom period", ErrInvalidQuery)
+		}
+		first := startOfDay(origin)
+		return bucketer{
+			start: func(t time.Time) time.Time {
+				return first.AddDate(0, 0, daysBetween(first, t)/days*days)
+			},
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 0, days) },
+			label: func(t time.Time) string { return t.Format(time.DateOnly) },
+		}, nil
+	}
+	return bucketer{}, fmt.Errorf("%w: period must be one of day, week, month, custom", ErrInvalidQuery)
+}
+
+func defaultLookback(period string, days int) func(time.Time) time.Time {
+	switch period {
+	case "day":
+		return func(t time.Time) time.Time { return startOfDay(t).AddDate(0, 0, -29) }
+	case "month":
+		return func(t time.Time) time.Time { return startOfMonth(t).AddDate(0, -11, 0) }
+	case "custom":
+		return func(t time.Time) time.Time { return startOfDay(t).AddDate(0, 0, -12*days+1) }
+	}
+	return func(t time.Time) time.Time { return startOfISOWeek(t).AddDate(0, 0, -11*7) }
+}
+
+type volumeAccumulator struct {
+	bucket    models.VolumeBucket
+	workouts  map[int64]bool
+	breakdown map[int64]*models.VolumeBreakdown
+}
+
+func (service *AnalyticsService) GetVolume(userId int64, q models.VolumeQuery) (models.VolumeAnalytics, error) {
+	switch q.GroupBy {
+	case "":
+		q.GroupBy = "exercise"
+	case "exercise", "category", "muscleGroup":
+	default:
+		return models.VolumeAnalytics{}, fmt.Errorf("%w: groupBy must be one of exercise, category, muscleGroup", ErrInvalidQuery)
+	}
+	if q.Period == "" {
+		q.Period = "week"
+	}
+	if q.Period != "custom" {
+		q.Days = 0
+	}
+
+	loc, err := userLocation(service.UserRepo, userId)
+	if err != nil {
+		return models.VolumeAnalytics{}, err
+	}
+	unit, err := resolveWeightUnit(service.UserRepo, userId, q.Unit)
+	if err != nil {
+		return models.VolumeAnalytics{}, err
+	}
+	from, to, err := resolveRange(q.From, q.To, loc, defaultLookback(q.Period, q.Days))
+	if err != nil {
+		return models.VolumeAnalytics{}, err
+	}
+	b, err := newBucketer(q.Period, q.Days, from)
+	if err != nil {
+		return models.VolumeAnalytics{}, err
+	}
+
+	var accs []*volumeAccumulator
+	index := map[int64]int{}
+	for start := b.start(from); !start.After(to); start = b.next(start) {
+		if len(accs) == maxAnalyticsBuckets {
+			return models.VolumeAnalytics{}, fmt.Errorf("%w: range spans more than %d buckets", ErrInvalidQuery, maxAnalyticsBuckets)
+		}
+		end := b.next(start)
+		index[start.Unix()] = len(accs)
+		accs = append(accs, &volumeAccumulator{
+			bucket: models.VolumeBucket{
+				Label: b.label(start),
+				Start: start.Format(time.RFC3339),
+				End:   end.Format(time.RFC3339),
+			},
+			workouts:  map[int64]bool{},
+			breakdown: map[int64]*models.VolumeBreakdown{},
+		})
+	}
+
+	// stored timestamps may carry any offset, so widen the SQL window and
+	// filter precisely after parsing
+	sets, err := service.AnalyticsRepo.ListSets(userId,
+		from.UTC().AddDate(0, 0, -1).Format(time.RFC3339),
+		to.UTC().AddDate(0, 0, 1).Format(time.RFC3339),
+		q.ExcludeWarmups,
+	)
+	if err != nil {
+		return models.VolumeAnalytics{}, err
+	}
+	var secondary map[int64][]models.NamedRef
+	if q.GroupBy == "muscleGroup" {
+		secondary, err = service.AnalyticsRepo.ListSecondaryMuscleGroups(userId)
+		if err != nil {
+			return models.VolumeAnalytics{}, err
+		}
+	}
+
+	result := models.VolumeAnalytics{
+		Period:     q.Period,
+		Days:       q.Days,
+		GroupBy:    q.GroupBy,
+		Timezone:   loc.String(),
+		WeightUnit: unit,
+		From:       from.Format(time.RFC3339),
+		To:         to.Format(time.RFC3339),
+		Buckets:    []models.VolumeBucket{},
+	}
+	allWorkouts := map[int64]bool{}
+
+	for _, s := range sets {
+		performedAt, err := parseStoredTime(s.PerformedAt)
+		if err != nil {
+			log.Printf("[Analytics] skipping workout=%d: %v", s.WorkoutId, err)
+			continue
+		}
+		performedAt = performedAt.In(loc)
+		if performedAt.Before(from) || performedAt.After(to) {
+			continue
+		}
+
+		i, ok := index[b.start(performedAt).Unix()]
+		if !ok {
+			log.Printf("[Analytics] no bucket for workout=%d at %s", s.WorkoutId, performedAt.Format(time.RFC3339))
+			continue
+		}
+		acc := accs[i]
+		reps, volume := 0, 0.0
+		if s.Reps != nil {
+			reps = *s.Reps
+			if s.Load != nil {
+				volume = float64(reps) * *s.Load
+			}
+		}
+
+		acc.workouts[s.WorkoutId] = true
+		allWorkouts[s.WorkoutId] = true
+		acc.bucket.Sets++
+		acc.bucket.Reps += reps
+		acc.bucket.Volume += volume
+
+		for _, ref := range breakdownRefs(q.GroupBy, s, secondary) {
+			bd, ok := acc.breakdown[ref.Id]
+			if !ok {
+				bd = &models.VolumeBreakdown{Id: ref.Id, Name: ref.Name}
+				acc.breakdown[ref.Id] = bd
+			}
+			bd.Sets++
+			bd.Reps += reps
+			bd.Volume += volume
+		}
+	}
+
+	for _, acc := range accs {
+		bucket := acc.bucket
+		bucket.Workouts = len(acc.workouts)
+		bucket.Volume = fromKg(unit, bucket.Volume)
+		bucket.Breakdown = make([]models.VolumeBreakdown, 0, len(acc.breakdown))
+		for _, bd := range acc.breakdown {
+			bd.Volume = fromKg(unit, bd.Volume)
+			bucket.Breakdown = append(bucket.Breakdown, *bd)
+		}
+		sort.Slice(bucket.Breakdown, func(i, j int) bool {
+			if bucket.Breakdown[i].Volume != bucket.Breakdown[j].Volume {
+				return bucket.Breakdown[i].Volume > bucket.Breakdown[j].Volume
+			}
+			return bucket.Breakdown[i].Name < bucket.Breakdown[j].Name
+		})
+
+		result.Totals.Sets += bucket.Sets
+		result.Totals.Reps += bucket.Reps
+		result.Totals.Volume += bucket.Volume
+		result.Buckets = append(result.Buckets, bucket)
+	}
+	result.Totals.Workouts = len(allWorkouts)
+	result.Totals.Volume = roundTo(result.Totals.Volume, 2)
+	return result, nil
+}
+
+// breakdownRefs lists the groups a set counts towards. For muscle groups
+// that is the primary plus every secondary muscle group.
+func breakdownRefs(groupBy string, s models.AnalyticsSet, secondary map[int64][]models.NamedRef) []models.NamedRef {
+	switch groupBy {
+	case "category":
+		return []models.NamedRef{{Id: s.CategoryId, Name: s.CategoryName}}
+	case "muscleGroup":
+		var refs []models.NamedRef
+		seen := map[int64]bool{}
+		if s.MuscleGroupId != nil && s.MuscleGroupName != nil {
+			refs = append(refs, models.NamedRef{Id: *s.MuscleGroupId, Name: *s.MuscleGroupName})
+			seen[*s.MuscleGroupId] = true
+		}
+		for _, mg := range secondary[s.ExerciseId] {
+			if !seen[mg.Id] {
+				refs = append(refs, mg)
+				seen[mg.Id] = true
+			}
+		}
+		return refs
+	}
+	return []models.NamedRef{{Id: s.ExerciseId, Name: s.ExerciseName}}
+}
+
+// streaks returns the current and longest run of consecutive periods in
+// sorted, distinct period starts. step is the period length in days and a
+// run is still current if its last period is this one or the one before.
+func streaks(starts []time.Time, current time.Time, step int) (int, int) {
+	longest, run := 0, 0
+	for i, start := range starts {
+		if i > 0 && daysBetween(starts[i-1], start) == step {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	if len(starts) == 0 || daysBetween(starts[len(starts)-1], current) > step {
+		return 0, longest
+	}
+	return run, longest
+}
+
+func (service *AnalyticsService) GetCalendar(userId int64, fromRaw, toRaw, unit string, excludeWarmups bool) (models.Calendar, error) {
+	loc, err := userLocation(service.UserRepo, userId)
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	unit, err = resolveWeightUnit(service.UserRepo, userId, unit)
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	if toRaw == "" {
+		toRaw = time.Now().In(loc).Format(time.DateOnly)
+	}
+	from, to, err := resolveRange(fromRaw, toRaw, loc, func(t time.Time) time.Time {
+		return startOfDay(t).AddDate(-1, 0, 1)
+	})
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	from = startOfDay(from)
+	if daysBetween(from, to) >= 3*366 {
+		return models.Calendar{}, fmt.Errorf("%w: range must be at most three years", ErrInvalidQuery)
+	}
+
+	calendar := models.Calendar{
+		Timezone:   loc.String(),
+		WeightUnit: unit,
+		From:       from.Format(time.RFC3339),
+		To:         to.Format(time.RFC3339),
+		Days:       []models.CalendarDay{},
+	}
+	index := map[string]int{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		index[date] = len(calendar.Days)
+		calendar.Days = append(calendar.Days, models.CalendarDay{Date: date})
+	}
+
+	workouts, err := service.AnalyticsRepo.ListCalendarWorkouts(userId,
+		from.UTC().AddDate(0, 0, -1).Format(time.RFC3339),
+		to.UTC().AddDate(0, 0, 1).Format(time.RFC3339),
+		excludeWarmups,
+	)
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	for _, w := range workouts {
+		performedAt, err := parseStoredTime(w.PerformedAt)
+		if err != nil {
+			log.Printf("[Analytics] skipping workout=%d: %v", w.Id, err)
+			continue
+		}
+		performedAt = performedAt.In(loc)
+		if performedAt.Before(from) || performedAt.After(to) {
+			continue
+		}
+
+		day := &calendar.Days[index[performedAt.Format(time.DateOnly)]]
+		if day.Workouts == 0 {
+			calendar.ActiveDays++
+		}
+		day.Workouts++
+		if w.DurationMinutes != nil {
+			day.DurationMinutes += *w.DurationMinutes
+		}
+		day.Volume = roundTo(day.Volume+fromKg(unit, w.Volume), 2)
+		calendar.TotalWorkouts++
+	}
+
+	weeks := float64(daysBetween(from, to)+1) / 7
+	calendar.Streaks.WorkoutsPerWeek = roundTo(float64(calendar.TotalWorkouts)/weeks, 2)
+
+	// streaks look at the whole history, not just the requested range
+	times, err := service.AnalyticsRepo.ListWorkoutTimes(userId)
+	if err != nil {
+		return models.Calendar{}, err
+	}
+	var days, weekStarts []time.Time
+	for _, raw := range times {
+		t, err := parseStoredTime(raw)
+		if err != nil {
+			continue
+		}
+		days = append(days, startOfDay(t.In(loc)))
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	days = compactDays(days)
+	for _, day := range days {
+		weekStarts = append(weekStarts, startOfISOWeek(day))
+	}
+	weekStarts = compactDays(weekStarts)
+
+	now := time.Now().In(loc)
+	calendar.Streaks.CurrentDaily, calendar.Streaks.LongestDaily = streaks(days, startOfDay(now), 1)
+	calendar.Streaks.CurrentWeekly, calendar.Streaks.LongestWeekly = streaks(weekStarts, startOfISOWeek(now), 7)
+	return calendar, nil
+}
+
+func compactDays(days []time.Time) []time.Time {
+	out := days[:0]
+	for i, day := range days {
+		if i == 0 || !day.Equal(days[i-1]) {
+			out = append(out, day)
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func days(s ...string) []time.Time {
+	out := make([]time.Time, 0, len(s))
+	for _, v := range s {
+		out = append(out, day(v))
+	}
+	return out
+}
+
+func TestNewBucketer(t *testing.T) {
+	tests := []struct {
+		name      string
+		period    string
+		days      int
+		at        string
+		wantStart string
+		wantNext  string
+		wantLabel string
+	}{
+		{"day", "day", 0, "2026-01-07", "2026-01-07", "2026-01-08", "2026-01-07"},
+		{"week starts on monday", "week", 0, "2026-01-11", "2026-01-05", "2026-01-12", "2026-W02"},
+		{"default is week", "", 0, "2026-01-01", "2025-12-29", "2026-01-05", "2026-W01"},
+		{"month", "month", 0, "2026-02-17", "2026-02-01", "2026-03-01", "2026-02"},
+		{"custom counts from the origin", "custom", 10, "2026-01-25", "2026-01-21", "2026-01-31", "2026-01-21"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newBucketer(tt.period, tt.days, day("2026-01-01"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := b.start(day(tt.at))
+			if !start.Equal(day(tt.wantStart)) || !b.next(start).Equal(day(tt.wantNext)) || b.label(start) != tt.wantLabel {
+				t.Errorf("bucket of %s = %s (next %s, label %q), want %s (next %s, label %q)",
+					tt.at, start.Format(time.DateOnly), b.next(start).Format(time.DateOnly), b.label(start),
+					tt.wantStart, tt.wantNext, tt.wantLabel)
+			}
+		})
+	}
+
+	for _, period := range []string{"year", "custom"} {
+		if _, err := newBucketer(period, 0, day("2026-01-01")); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("newBucketer(%q, 0) err = %v, want ErrInvalidQuery", period, err)
+		}
+	}
+}
+
+func TestResolveRange(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	from, to, err := resolveRange("2026-01-05", "2026-01-11", berlin, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 1, 4, 23, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("from = %s, want %s", from.UTC(), want)
+	}
+	if want := time.Date(2026, 1, 11, 22, 59, 59, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("to = %s, want the end of the day %s", to.UTC(), want)
+	}
+
+	from, _, err = resolveRange("", "2026-01-11", berlin, startOfISOWeek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 1, 5, 0, 0, 0, 0, berlin); !from.Equal(want) {
+		t.Errorf("default from = %s, want %s", from, want)
+	}
+
+	if _, _, err := resolveRange("2026-02-01", "2026-01-01", berlin, nil); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("reversed range err = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		name        string
+		starts      []time.Time
+		current     string
+		step        int
+		wantCurrent int
+		wantLongest int
+	}{
+		{"nothing logged", nil, "2026-01-05", 1, 0, 0},
+		{"run ending today", days("2026-01-01", "2026-01-02", "2026-01-03", "2026-01-05"), "2026-01-05", 1, 1, 3},
+		{"run ending yesterday is still current", days("2026-01-01", "2026-01-02", "2026-01-03"), "2026-01-04", 1, 3, 3},
+		{"run broken", days("2026-01-01", "2026-01-02", "2026-01-03"), "2026-01-05", 1, 0, 3},
+		{"weeks", days("2025-12-29", "2026-01-05", "2026-01-12"), "2026-01-19", 7, 3, 3},
+		{"skipped week", days("2025-12-22", "2026-01-05", "2026-01-12"), "2026-01-12", 7, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := streaks(tt.starts, day(tt.current), tt.step)
+			if current != tt.wantCurrent || longest != tt.wantLongest {
+				t.Errorf("streaks = (%d, %d), want (%d, %d)", current, longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
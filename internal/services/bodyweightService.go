@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+const maxBodyweightKg = 500
+
+type BodyweightService struct {
+	Repo          *repo.BodyweightRepo
+	UserRepo      *repo.UserRepo
+	RecordService *RecordService
+}
+
+func NewBodyweightService(br *repo.BodyweightRepo, ur *repo.UserRepo, rs *RecordService) *BodyweightService {
+	return &BodyweightService{
+		Repo:          br,
+		UserRepo:      ur,
+		RecordService: rs,
+	}
+}
+
+func convertBodyweight(unit string, b *models.Bodyweight) {
+	b.Weight = fromKg(unit, b.Weight)
+	b.Unit = unit
+}
+
+// LogBodyweight stores a bodyweight entry. measuredAt defaults to now; a
+// bare date means the start of that day in the user's timezone.
+func (service *BodyweightService) LogBodyweight(userId int64, weight float64, unit, measuredAt string) (models.Bodyweight, error) {
+	prefs, err := service.UserRepo.GetPreferences(userId)
+	if err != nil {
+		return models.Bodyweight{}, err
+	}
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		unit = prefs.WeightUnit
+	}
+	if err := validateWeightUnit(unit); err != nil {
+		return models.Bodyweight{}, err
+	}
+	kg := *toKg(unit, &weight)
+	if kg <= 0 || kg > maxBodyweightKg {
+		return models.Bodyweight{}, &FieldError{Field: "weight", Message: fmt.Sprintf("must be between 0 and %d kg", maxBodyweightKg)}
+	}
+
+	measuredAt = strings.TrimSpace(measuredAt)
+	switch {
+	case measuredAt == "":
+		measuredAt = time.Now().UTC().Format(time.RFC3339)
+	default:
+		if loc, err := time.LoadLocation(prefs.Timezone); err == nil {
+			if day, err := time.ParseInLocation(time.DateOnly, measuredAt, loc); err == nil {
+				measuredAt = day.UTC().Format(time.RFC3339)
+				break
+			}
+		}
+		if measuredAt, _, err = normalizeTimestamp("measuredAt", measuredAt, nil); err != nil {
+			return models.Bodyweight{}, err
+		}
+	}
+
+	entry, err := service.Repo.Create(userId, kg, measuredAt)
+	if err != nil {
+		return models.Bodyweight{}, err
+	}
+	if err := service.recalculateFrom(userId, entry.MeasuredAt); err != nil {
+		return models.Bodyweight{}, err
+	}
+	convertBodyweight(unit, &entry)
+	return entry, nil
+}
+
+func (service *BodyweightService) ListBodyweights(userId int64, fromRaw, toRaw, unit string) ([]models.Bodyweight, error) {
+	unit, err := resolveWeightUnit(service.UserRepo, userId, unit)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := parseDateFilter(service.UserRepo, userId, fromRaw, toRaw)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := service.Repo.List(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		convertBodyweight(unit, &entries[i])
+	}
+	return entries, nil
+}
+
+func (service *BodyweightService) DeleteBodyweight(userId, id int64) error {
+	entry, err := service.Repo.Delete(userId, id)
+	if err != nil {
+		return err
+	}
+	return service.recalculateFrom(userId, entry.MeasuredAt)
+}
+
+// recalculateFrom rebuilds the records a weigh-in measured at measuredAt
+// feeds: workouts from then on, and the ones before it too when no earlier
+// entry exists, since those fall back to the first one.
+func (service *BodyweightService) recalculateFrom(userId int64, measuredAt string) error {
+	earlier, err := service.Repo.HasEntryBefore(userId, measuredAt)
+	if err != nil {
+		return err
+	}
+	if !earlier {
+		measuredAt = ""
+	}
+	return service.RecordService.RecalculateBodyweightExercises(userId, measuredAt)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+var ErrCategoryInUse = errors.New("category is used by one or more exercises")
+
+type CategoryService struct {
+	Repo *repo.CategoryRepo
+}
+
+func NewCategoryService(repo *repo.CategoryRepo) *CategoryService {
+	return &CategoryService{Repo: repo}
+}
+
+func (service *CategoryService) ListCategories(userId int64) ([]models.Category, error) {
+	return service.Repo.ListCategories(userId)
+}
+
+func (service *CategoryService) GetCategory(userId, categoryId int64) (models.Category, error) {
+	return service.Repo.GetCategoryById(userId, categoryId)
+}
+
+func (service *CategoryService) CreateCategory(userId int64, name string) (models.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Category{}, fmt.Errorf("name is required")
+	}
+	return service.Repo.CreateCategory(userId, name)
+}
+
+func (service *CategoryService) RenameCategory(userId, categoryId int64, name string) (models.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Category{}, fmt.Errorf("name is required")
+	}
+	return service.Repo.RenameCategory(userId, categoryId, name)
+}
+
+func (service *CategoryService) DeleteCategory(categoryId int64) error {
+	refs, err := service.Repo.CountExerciseReferences(categoryId)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return ErrCategoryInUse
+	}
+	return service.Repo.DeleteCategory(categoryId)
+}
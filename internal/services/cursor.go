@@ -0,0 +1,40 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"workout-tracker/internal/models"
+)
+
+func encodeCursor(sort string, c *models.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	c.Sort = sort
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses an opaque cursor and checks it was issued for the
+// same sort.
+func decodeCursor(token, sort string) (*models.Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+	var c models.Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+	if c.Sort != sort {
+		return nil, fmt.Errorf("%w: cursor does not match sort and order", ErrInvalidQuery)
+	}
+	return &c, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		in   models.Cursor
+		key  any
+	}{
+		{"string key", "date:desc", models.Cursor{Key: "2026-01-09T17:30:00Z", Id: 7}, "2026-01-09T17:30:00Z"},
+		{"numeric key comes back as float64", "duration:asc", models.Cursor{Key: 45, Id: 3}, 45.0},
+		{"null key", "volume:desc", models.Cursor{Key: nil, Id: 12}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			token := encodeCursor(tt.sort, &in)
+			got, err := decodeCursor(token, tt.sort)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if got.Key != tt.key || got.Id != tt.in.Id || got.Sort != tt.sort {
+				t.Errorf("got %+v, want key %v id %d sort %q", *got, tt.key, tt.in.Id, tt.sort)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	other := encodeCursor("date:asc", &models.Cursor{Key: "x", Id: 1})
+	tests := []struct {
+		name    string
+		token   string
+		wantNil bool
+		wantErr bool
+	}{
+		{"empty token is the first page", "", true, false},
+		{"not base64", "!!!", true, true},
+		{"not json", "bm90IGpzb24", true, true},
+		{"issued for another sort", other, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.token, "date:desc")
+			if (got == nil) != tt.wantNil {
+				t.Errorf("cursor = %+v, want nil %v", got, tt.wantNil)
+			}
+			if tt.wantErr != (err != nil) || (err != nil && !errors.Is(err, ErrInvalidQuery)) {
+				t.Errorf("err = %v, want ErrInvalidQuery %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if token := encodeCursor("date:desc", nil); token != "" {
+		t.Errorf("encodeCursor(nil) = %q, want empty", token)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"workout-tracker/internal/repo"
+)
+
+var storedTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+}
+
+// parseStoredTime reads a performed_at value written by any client; values
+// without an offset are taken as UTC.
+func parseStoredTime(value string) (time.Time, error) {
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
+
+// parseRangeBound parses an RFC3339 timestamp or a YYYY-MM-DD date in loc.
+// A bare date used as an upper bound covers the whole day.
+func parseRangeBound(name, value string, loc *time.Location, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.In(loc)
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be RFC3339 or YYYY-MM-DD", ErrInvalidQuery, name)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return &t, nil
+}
+
+// userLocation is the user's preferred timezone, or UTC when it is unknown.
+func userLocation(users *repo.UserRepo, userId int64) (*time.Location, error) {
+	prefs, err := users.GetPreferences(userId)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// parseDateFilter parses optional from/to query bounds, reading bare dates
+// in the user's timezone like the analytics ranges do.
+func parseDateFilter(users *repo.UserRepo, userId int64, fromRaw, toRaw string) (*time.Time, *time.Time, error) {
+	fromRaw, toRaw = strings.TrimSpace(fromRaw), strings.TrimSpace(toRaw)
+	if fromRaw == "" && toRaw == "" {
+		return nil, nil, nil
+	}
+	loc, err := userLocation(users, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+	from, err := parseRangeBound("from", fromRaw, loc, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseRangeBound("to", toRaw, loc, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, fmt.Errorf("%w: from must be before to", ErrInvalidQuery)
+	}
+	return from, to, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func startOfISOWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func startOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween counts calendar days from a to b, ignoring DST shifts.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	ua := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	ub := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// FieldError is a validation error tied to a single request field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// normalizeTimestamp parses a client timestamp and returns it in UTC
+// RFC3339 along with the offset it was given in. Timestamps without an
+// offset are only accepted together with an IANA timezone.
+func normalizeTimestamp(field, value string, timezone *string) (string, string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", "", &FieldError{Field: field, Message: "is required"}
+	}
+
+	var loc *time.Location
+	if timezone != nil {
+		var err error
+		if loc, err = time.LoadLocation(*timezone); err != nil || *timezone == "" {
+			return "", "", &FieldError{Field: "timezone", Message: "must be an IANA zone name like Europe/Berlin"}
+		}
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if loc == nil {
+			return "", "", &FieldError{Field: field, Message: "must be an RFC3339 timestamp like 2026-01-09T18:30:00+01:00"}
+		}
+		if t, err = time.ParseInLocation("2006-01-02T15:04:05", value, loc); err != nil {
+			return "", "", &FieldError{Field: field, Message: "must be an RFC3339 timestamp or a local time like 2026-01-09T18:30:00"}
+		}
+	}
+	if t.Year() < 1900 || t.Year() > 2200 {
+		return "", "", &FieldError{Field: field, Message: "is out of range"}
+	}
+
+	return t.UTC().Format(time.RFC3339), t.Format("-07:00"), nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeTimestamp(t *testing.T) {
+	berlin := "Europe/Berlin"
+	tests := []struct {
+		name       string
+		value      string
+		timezone   *string
+		wantUTC    string
+		wantOffset string
+		wantField  string
+	}{
+		{"offset", "2026-01-09T18:30:00+01:00", nil, "2026-01-09T17:30:00Z", "+01:00", ""},
+		{"utc with spaces", " 2026-01-09T18:30:00Z ", nil, "2026-01-09T18:30:00Z", "+00:00", ""},
+		{"offset wins over zone", "2026-01-09T18:30:00-05:00", &berlin, "2026-01-09T23:30:00Z", "-05:00", ""},
+		{"local time in winter", "2026-01-09T18:30:00", &berlin, "2026-01-09T17:30:00Z", "+01:00", ""},
+		{"local time in summer", "2026-07-09T18:30:00", &berlin, "2026-07-09T16:30:00Z", "+02:00", ""},
+		{"local time without zone", "2026-01-09T18:30:00", nil, "", "", "performedAt"},
+		{"missing", "  ", nil, "", "", "performedAt"},
+		{"unknown zone", "2026-01-09T18:30:00", ptr("Mars/Base"), "", "", "timezone"},
+		{"empty zone", "2026-01-09T18:30:00", ptr(""), "", "", "timezone"},
+		{"garbage", "yesterday", &berlin, "", "", "performedAt"},
+		{"out of range", "1800-01-01T00:00:00Z", nil, "", "", "performedAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utc, offset, err := normalizeTimestamp("performedAt", tt.value, tt.timezone)
+			if tt.wantField != "" {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField {
+					t.Fatalf("err = %v, want a FieldError on %s", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if utc != tt.wantUTC || offset != tt.wantOffset {
+				t.Errorf("got (%s, %s), want (%s, %s)", utc, offset, tt.wantUTC, tt.wantOffset)
+			}
+		})
+	}
+}
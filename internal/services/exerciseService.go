@@ -1,23 +1,249 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"workout-tracker/internal/models"
 	"workout-tracker/internal/repo"
 )
 
+var ErrExerciseInUse = errors.New("exercise is used in one or more workouts or routines")
+var ErrInvalidQuery = errors.New("invalid query")
+var ErrMetricTypeInUse = errors.New("metricType cannot change while the exercise is used in workouts or routines")
+
 type ExerciseService struct {
-	Repo *repo.ExerciseRepo
+	Repo          *repo.ExerciseRepo
+	UserRepo      *repo.UserRepo
+	RecordService *RecordService
+}
+
+func NewExerciseService(repo *repo.ExerciseRepo, ur *repo.UserRepo, rs *RecordService) *ExerciseService {
+	return &ExerciseService{Repo: repo, UserRepo: ur, RecordService: rs}
+}
+
+func (service *ExerciseService) ListAllExercises(userId int64, filter models.ExerciseFilter) (models.ExerciseList, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	switch filter.Owner {
+	case "", "all", "global", "mine":
+	default:
+		return models.ExerciseList{}, fmt.Errorf("%w: owner must be one of all, global, mine", ErrInvalidQuery)
+	}
+	switch filter.Sort {
+	case "":
+		filter.Sort = "name"
+	case "name", "createdAt", "category":
+	default:
+		return models.ExerciseList{}, fmt.Errorf("%w: sort must be one of name, createdAt, category", ErrInvalidQuery)
+	}
+	switch filter.Order {
+	case "":
+		filter.Order = "asc"
+	case "asc", "desc":
+	default:
+		return models.ExerciseList{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidQuery)
+	}
+
+	sortKey := filter.Sort + ":" + filter.Order
+	after, err := decodeCursor(filter.Cursor, sortKey)
+	if err != nil {
+		return models.ExerciseList{}, err
+	}
+	filter.After = after
+	if after != nil {
+		filter.Offset = 0
+	}
+
+	out, total, next, err := service.Repo.ListAllExercises(userId, filter)
+	if err != nil {
+		return models.ExerciseList{}, err
+	}
+	return models.ExerciseList{
+		Exercises:  out,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		NextCursor: encodeCursor(sortKey, next),
+	}, nil
+}
+
+func normalizeMetricType(metricType string) (string, error) {
+	metricType = strings.ToLower(strings.TrimSpace(metricType))
+	if metricType == "" {
+		return models.MetricWeightReps, nil
+	}
+	if !slices.Contains(models.MetricTypes, metricType) {
+		return "", &FieldError{Field: "metricType", Message: "must be one of " + strings.Join(models.MetricTypes, ", ")}
+	}
+	return metricType, nil
+}
+
+// normalizeLoadType defaults to external load. Plain bodyweight exercises
+// may be logged in reps alone; assisted and weighted ones need weight_reps
+// to record the assistance or the added load.
+func normalizeLoadType(loadType, metricType string) (string, error) {
+	loadType = strings.ToLower(strings.TrimSpace(loadType))
+	if loadType == "" {
+		return models.LoadExternal, nil
+	}
+	if !slices.Contains(models.LoadTypes, loadType) {
+		return "", &FieldError{Field: "loadType", Message: "must be one of " + strings.Join(models.LoadTypes, ", ")}
+	}
+	switch {
+	case loadType == models.LoadBodyweight && metricType != models.MetricWeightReps && metricType != models.MetricReps:
+		return "", &FieldError{Field: "loadType", Message: fmt.Sprintf("%s requires metricType %s or %s", loadType, models.MetricWeightReps, models.MetricReps)}
+	case loadType != models.LoadExternal && loadType != models.LoadBodyweight && metricType != models.MetricWeightReps:
+		return "", &FieldError{Field: "loadType", Message: fmt.Sprintf("%s requires metricType %s", loadType, models.MetricWeightReps)}
+	}
+	return loadType, nil
+}
+
+func (service *ExerciseService) validateExercise(name string, categoryId int64, muscleGroupId *int64) error {
+	if strings.TrimSpace(name) == "" {
+		return &FieldError{Field: "name", Message: "is required"}
+	}
+	ok, err := service.Repo.CategoryExists(categoryId)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &FieldError{Field: "categoryId", Message: fmt.Sprintf("category %d does not exist", categoryId)}
+	}
+	if muscleGroupId != nil {
+		ok, err := service.Repo.MuscleGroupExists(*muscleGroupId)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return &FieldError{Field: "muscleGroupId", Message: fmt.Sprintf("muscle group %d does not exist", *muscleGroupId)}
+		}
+	}
+	return nil
 }
 
-func NewExerciseService(repo *repo.ExerciseRepo) *ExerciseService {
-	return &ExerciseService{Repo: repo}
+func (service *ExerciseService) mustOwnExercise(userId, exerciseId int64) (models.Exercise, error) {
+	ex, err := service.Repo.GetExerciseById(exerciseId)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if ex.OwnerUserId == nil {
+		isAdmin, err := service.UserRepo.IsAdmin(userId)
+		if err != nil {
+			return models.Exercise{}, err
+		}
+		if !isAdmin {
+			return models.Exercise{}, ErrForbidden
+		}
+		return ex, nil
+	}
+	if *ex.OwnerUserId != userId {
+		return models.Exercise{}, repo.ErrNotFound
+	}
+	return ex, nil
+}
+
+func (service *ExerciseService) CreateExercise(userId int64, name string, categoryId int64, muscleGroupId *int64, metricType, loadType string) (models.Exercise, error) {
+	name = strings.TrimSpace(name)
+	if err := service.validateExercise(name, categoryId, muscleGroupId); err != nil {
+		return models.Exercise{}, err
+	}
+	metricType, err := normalizeMetricType(metricType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	loadType, err = normalizeLoadType(loadType, metricType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	return service.Repo.CreateExercise(userId, name, categoryId, muscleGroupId, metricType, loadType)
+}
+
+func (service *ExerciseService) UpdateExercise(userId, exerciseId int64, name string, categoryId int64, muscleGroupId *int64, metricType, loadType string) (models.Exercise, error) {
+	ex, err := service.mustOwnExercise(userId, exerciseId)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	name = strings.TrimSpace(name)
+	if err := service.validateExercise(name, categoryId, muscleGroupId); err != nil {
+		return models.Exercise{}, err
+	}
+	if strings.TrimSpace(metricType) == "" {
+		metricType = ex.MetricType
+	}
+	metricType, err = normalizeMetricType(metricType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if metricType != ex.MetricType {
+		refs, err := service.Repo.CountReferences(exerciseId)
+		if err != nil {
+			return models.Exercise{}, err
+		}
+		if refs > 0 {
+			return models.Exercise{}, ErrMetricTypeInUse
+		}
+	}
+	if strings.TrimSpace(loadType) == "" {
+		loadType = ex.LoadType
+	}
+	loadType, err = normalizeLoadType(loadType, metricType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+
+	updated, err := service.Repo.UpdateExercise(ex.OwnerUserId, exerciseId, name, categoryId, muscleGroupId, metricType, loadType)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if loadType != ex.LoadType {
+		if err := service.RecordService.RecalculateExerciseForAll(exerciseId); err != nil {
+			return models.Exercise{}, err
+		}
+	}
+	return updated, nil
 }
 
-func (service *ExerciseService) ListAllExercises() ([]models.Exercise, error) {
-	out, err := service.Repo.ListAllExercises()
+func (service *ExerciseService) SetSecondaryMuscleGroups(userId, exerciseId int64, muscleGroupIds []int64) (models.Exercise, error) {
+	ex, err := service.mustOwnExercise(userId, exerciseId)
 	if err != nil {
-		return nil, err
+		return models.Exercise{}, err
+	}
+	for _, id := range muscleGroupIds {
+		if ex.MuscleGroupId != nil && *ex.MuscleGroupId == id {
+			return models.Exercise{}, fmt.Errorf("muscle group %d is already the primary muscle group", id)
+		}
+		ok, err := service.Repo.MuscleGroupExists(id)
+		if err != nil {
+			return models.Exercise{}, err
+		}
+		if !ok {
+			return models.Exercise{}, fmt.Errorf("muscle group %d does not exist", id)
+		}
 	}
-	return out, nil
+	return service.Repo.SetSecondaryMuscleGroups(exerciseId, muscleGroupIds)
+}
 
+func (service *ExerciseService) DeleteExercise(userId, exerciseId int64) error {
+	ex, err := service.mustOwnExercise(userId, exerciseId)
+	if err != nil {
+		return err
+	}
+	refs, err := service.Repo.CountReferences(exerciseId)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return ErrExerciseInUse
+	}
+	return service.Repo.DeleteExercise(ex.OwnerUserId, exerciseId)
 }
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestNormalizeLoadType(t *testing.T) {
+	tests := []struct {
+		name       string
+		loadType   string
+		metricType string
+		want       string
+		wantErr    bool
+	}{
+		{"defaults to external", " ", models.MetricTime, models.LoadExternal, false},
+		{"case and spaces", " Assisted ", models.MetricWeightReps, models.LoadAssisted, false},
+		{"bodyweight in reps", models.LoadBodyweight, models.MetricReps, models.LoadBodyweight, false},
+		{"bodyweight with added weight", models.LoadBodyweight, models.MetricWeightReps, models.LoadBodyweight, false},
+		{"bodyweight needs reps", models.LoadBodyweight, models.MetricTime, "", true},
+		{"assisted needs weight", models.LoadAssisted, models.MetricReps, "", true},
+		{"weighted needs weight", models.LoadWeightedBodyweight, models.MetricReps, "", true},
+		{"unknown", "sandbag", models.MetricWeightReps, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeLoadType(tt.loadType, tt.metricType)
+			if tt.wantErr {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "loadType" {
+					t.Fatalf("err = %v, want a FieldError on loadType", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeLoadType(%q, %q) = %q, %v, want %q", tt.loadType, tt.metricType, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"workout-tracker/internal/models"
+)
+
+const (
+	maxGroupLabelLength = 20
+	maxGroupRounds      = 100
+)
+
+func validateGroup(group *models.ExerciseGroup) error {
+	group.Label = strings.TrimSpace(group.Label)
+	if group.Label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if len(group.Label) > maxGroupLabelLength {
+		return fmt.Errorf("label must be at most %d characters", maxGroupLabelLength)
+	}
+	group.GroupType = strings.ToLower(strings.TrimSpace(group.GroupType))
+	if !slices.Contains(models.GroupTypes, group.GroupType) {
+		return fmt.Errorf("groupType must be one of %s", strings.Join(models.GroupTypes, ", "))
+	}
+	if group.Rounds != nil && (*group.Rounds < 1 || *group.Rounds > maxGroupRounds) {
+		return fmt.Errorf("rounds must be between 1 and %d", maxGroupRounds)
+	}
+	if group.RestSeconds != nil && (*group.RestSeconds < 0 || *group.RestSeconds > maxRestSeconds) {
+		return fmt.Errorf("restSeconds must be between 0 and %d", maxRestSeconds)
+	}
+	return nil
+}
+
+// normalizeGroupLabel trims a member's group label, treating a blank one as
+// no group.
+func normalizeGroupLabel(label *string) *string {
+	if label == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*label)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// validateGroups checks the groups of a workout or routine document and
+// returns their labels for memberGroupLabel.
+func validateGroups(groups []models.ExerciseGroup) ([]string, error) {
+	labels := make([]string, 0, len(groups))
+	for i := range groups {
+		if err := validateGroup(&groups[i]); err != nil {
+			return nil, fmt.Errorf("groups[%d]: %w", i, err)
+		}
+		if slices.Contains(labels, groups[i].Label) {
+			return nil, fmt.Errorf("groups[%d]: duplicate label %q", i, groups[i].Label)
+		}
+		labels = append(labels, groups[i].Label)
+	}
+	return labels, nil
+}
+
+// memberGroupLabel normalizes the groupLabel of exercises[i] and checks it
+// names one of labels, so exercises can only be grouped with others from the
+// same document.
+func memberGroupLabel(labels []string, i int, label *string) (*string, error) {
+	label = normalizeGroupLabel(label)
+	if label != nil && !slices.Contains(labels, *label) {
+		return nil, fmt.Errorf("exercises[%d]: groupLabel %q is not one of the groups", i, *label)
+	}
+	return label, nil
+}
+
+// nextGroupLabel picks the first letter not already used as a label.
+func nextGroupLabel(groups []models.ExerciseGroup) string {
+	used := map[string]bool{}
+	for _, g := range groups {
+		used[g.Label] = true
+	}
+	for c := 'A'; c <= 'Z'; c++ {
+		if !used[string(c)] {
+			return string(c)
+		}
+	}
+	for n := len(groups) + 1; ; n++ {
+		if label := fmt.Sprintf("G%d", n); !used[label] {
+			return label
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+type HistoryService struct {
+	HistoryRepo   *repo.HistoryRepo
+	RecordService *RecordService
+}
+
+func NewHistoryService(hr *repo.HistoryRepo, rs *RecordService) *HistoryService {
+	return &HistoryService{
+		HistoryRepo:   hr,
+		RecordService: rs,
+	}
+}
+
+// isTopSet reports whether candidate beats current: heavier load first,
+// then more reps.
+func isTopSet(candidate, current models.Set) bool {
+	cw, pw := 0.0, 0.0
+	if load := effectiveLoad(candidate); load != nil {
+		cw = *load
+	}
+	if load := effectiveLoad(current); load != nil {
+		pw = *load
+	}
+	if cw != pw {
+		return cw > pw
+	}
+	cr, pr := 0, 0
+	if candidate.Reps != nil {
+		cr = *candidate.Reps
+	}
+	if current.Reps != nil {
+		pr = *current.Reps
+	}
+	return cr > pr
+}
+
+func summarizeHistoryEntry(formula string, excludeWarmups bool, entry *models.ExerciseHistoryEntry) {
+	for i := range entry.Sets {
+		set := &entry.Sets[i]
+		load := effectiveLoad(*set)
+		set.E1RM = setOneRepMax(formula, set.Reps, load)
+		if excludeWarmups && set.SetType == models.SetTypeWarmup {
+			continue
+		}
+
+		entry.TotalSets++
+		if set.Reps != nil {
+			entry.TotalReps += *set.Reps
+			if load != nil {
+				entry.TotalVolume += float64(*set.Reps) * *load
+			}
+		}
+		if set.E1RM != nil && (entry.BestE1RM == nil || *set.E1RM > *entry.BestE1RM) {
+			best := *set.E1RM
+			entry.BestE1RM = &best
+		}
+		if set.Reps != nil && (entry.TopSet == nil || isTopSet(*set, *entry.TopSet)) {
+			top := *set
+			entry.TopSet = &top
+		}
+	}
+	entry.TotalVolume = roundTo(entry.TotalVolume, 2)
+}
+
+func convertHistoryEntry(unit string, entry *models.ExerciseHistoryEntry) {
+	for i := range entry.Sets {
+		convertSet(unit, &entry.Sets[i])
+	}
+	if entry.TopSet != nil {
+		convertSet(unit, entry.TopSet)
+	}
+	entry.TotalVolume = fromKg(unit, entry.TotalVolume)
+	entry.BestE1RM = fromKgPtr(unit, entry.BestE1RM)
+}
+
+func (service *HistoryService) GetExerciseHistory(userId, exerciseId int64, formula string, filter models.HistoryFilter) (models.ExerciseHistory, error) {
+	if err := service.RecordService.mustBeVisibleExercise(userId, exerciseId); err != nil {
+		return models.ExerciseHistory{}, err
+	}
+	formula, err := service.RecordService.ResolveFormula(userId, formula)
+	if err != nil {
+		return models.ExerciseHistory{}, err
+	}
+	unit, err := resolveWeightUnit(service.RecordService.UserRepo, userId, filter.Unit)
+	if err != nil {
+		return models.ExerciseHistory{}, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = 25
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	from, to, err := parseDateFilter(service.RecordService.UserRepo, userId, filter.FromRaw, filter.ToRaw)
+	if err != nil {
+		return models.ExerciseHistory{}, err
+	}
+	filter.From, filter.To = from, to
+
+	after, err := decodeCursor(filter.Cursor, "history")
+	if err != nil {
+		return models.ExerciseHistory{}, err
+	}
+	filter.After = after
+	if after != nil {
+		filter.Offset = 0
+	}
+
+	entries, total, next, err := service.HistoryRepo.ListExerciseHistory(userId, exerciseId, filter)
+	if err != nil {
+		return models.ExerciseHistory{}, err
+	}
+	for i := range entries {
+		summarizeHistoryEntry(formula, filter.ExcludeWarmups, &entries[i])
+		convertHistoryEntry(unit, &entries[i])
+	}
+
+	return models.ExerciseHistory{
+		ExerciseId: exerciseId,
+		Formula:    formula,
+		WeightUnit: unit,
+		Entries:    entries,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		NextCursor: encodeCursor("history", next),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+var ErrMuscleGroupInUse = errors.New("muscle group is the primary muscle group of one or more exercises")
+
+type MuscleGroupService struct {
+	Repo *repo.MuscleGroupRepo
+}
+
+func NewMuscleGroupService(repo *repo.MuscleGroupRepo) *MuscleGroupService {
+	return &MuscleGroupService{Repo: repo}
+}
+
+func (service *MuscleGroupService) ListMuscleGroups(userId int64) ([]models.MuscleGroup, error) {
+	return service.Repo.ListMuscleGroups(userId)
+}
+
+func (service *MuscleGroupService) GetMuscleGroup(userId, muscleGroupId int64) (models.MuscleGroup, error) {
+	return service.Repo.GetMuscleGroupById(userId, muscleGroupId)
+}
+
+func (service *MuscleGroupService) CreateMuscleGroup(userId int64, name string) (models.MuscleGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.MuscleGroup{}, fmt.Errorf("name is required")
+	}
+	return service.Repo.CreateMuscleGroup(userId, name)
+}
+
+func (service *MuscleGroupService) RenameMuscleGroup(userId, muscleGroupId int64, name string) (models.MuscleGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.MuscleGroup{}, fmt.Errorf("name is required")
+	}
+	return service.Repo.RenameMuscleGroup(userId, muscleGroupId, name)
+}
+
+func (service *MuscleGroupService) DeleteMuscleGroup(muscleGroupId int64) error {
+	refs, err := service.Repo.CountPrimaryExerciseReferences(muscleGroupId)
+	if err != nil {
+		return err
+	}
+	if refs > 0 {
+		return ErrMuscleGroupInUse
+	}
+	return service.Repo.DeleteMuscleGroup(muscleGroupId)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const DefaultE1RMFormula = "epley"
+
+var oneRepMaxFormulas = map[string]func(reps, weight float64) float64{
+	"epley": func(reps, weight float64) float64 {
+		return weight * (1 + reps/30)
+	},
+	"brzycki": func(reps, weight float64) float64 {
+		return weight * 36 / (37 - reps)
+	},
+	"lombardi": func(reps, weight float64) float64 {
+		return weight * math.Pow(reps, 0.10)
+	},
+	"mayhew": func(reps, weight float64) float64 {
+		return 100 * weight / (52.2 + 41.9*math.Exp(-0.055*reps))
+	},
+	"oconner": func(reps, weight float64) float64 {
+		return weight * (1 + 0.025*reps)
+	},
+	"wathan": func(reps, weight float64) float64 {
+		return 100 * weight / (48.8 + 53.8*math.Exp(-0.075*reps))
+	},
+}
+
+func E1RMFormulas() []string {
+	names := make([]string, 0, len(oneRepMaxFormulas))
+	for name := range oneRepMaxFormulas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateE1RMFormula(formula string) error {
+	if _, ok := oneRepMaxFormulas[formula]; !ok {
+		return fmt.Errorf("formula must be one of %s", strings.Join(E1RMFormulas(), ", "))
+	}
+	return nil
+}
+
+func roundTo(value float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(value*p) / p
+}
+
+func estimateOneRepMax(formula string, reps int, weight float64) float64 {
+	if reps <= 0 || weight <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+	// brzycki breaks down past 36 reps
+	if formula == "brzycki" && reps >= 37 {
+		return 0
+	}
+	f, ok := oneRepMaxFormulas[formula]
+	if !ok {
+		f = oneRepMaxFormulas[DefaultE1RMFormula]
+	}
+	return roundTo(f(float64(reps), weight), 2)
+}
+
+func setOneRepMax(formula string, reps *int, weight *float64) *float64 {
+	if reps == nil || weight == nil {
+		return nil
+	}
+	e1rm := estimateOneRepMax(formula, *reps, *weight)
+	if e1rm <= 0 {
+		return nil
+	}
+	return &e1rm
+}
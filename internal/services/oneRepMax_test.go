@@ -0,0 +1,32 @@
+package services
+
+import "testing"
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		reps    int
+		weight  float64
+		want    float64
+	}{
+		{"epley", "epley", 5, 100, 116.67},
+		{"brzycki", "brzycki", 5, 100, 112.5},
+		{"lombardi", "lombardi", 5, 100, 117.46},
+		{"mayhew", "mayhew", 5, 100, 119.01},
+		{"oconner", "oconner", 5, 100, 112.5},
+		{"wathan", "wathan", 5, 100, 116.58},
+		{"single rep is the weight", "mayhew", 1, 140, 140},
+		{"unknown formula falls back to epley", "nope", 5, 100, 116.67},
+		{"no reps", "epley", 0, 100, 0},
+		{"no weight", "epley", 5, 0, 0},
+		{"brzycki past 36 reps", "brzycki", 37, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateOneRepMax(tt.formula, tt.reps, tt.weight); got != tt.want {
+				t.Errorf("estimateOneRepMax(%q, %d, %v) = %v, want %v", tt.formula, tt.reps, tt.weight, got, tt.want)
+			}
+		})
+	}
+}
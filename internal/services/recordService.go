@@ -0,0 +1,363 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+type RecordService struct {
+	RecordRepo   *repo.RecordRepo
+	ExerciseRepo *repo.ExerciseRepo
+	UserRepo     *repo.UserRepo
+}
+
+func NewRecordService(rr *repo.RecordRepo, er *repo.ExerciseRepo, ur *repo.UserRepo) *RecordService {
+	return &RecordService{
+		RecordRepo:   rr,
+		ExerciseRepo: er,
+		UserRepo:     ur,
+	}
+}
+
+// ResolveFormula returns the requested e1RM formula, falling back to the
+// user's preference when none is given.
+func (service *RecordService) ResolveFormula(userId int64, formula string) (string, error) {
+	formula = strings.ToLower(strings.TrimSpace(formula))
+	if formula == "" {
+		prefs, err := service.UserRepo.GetPreferences(userId)
+		if err != nil {
+			return "", err
+		}
+		formula = prefs.E1RMFormula
+	}
+	if err := validateE1RMFormula(formula); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidQuery, err)
+	}
+	return formula, nil
+}
+
+func (service *RecordService) mustBeVisibleExercise(userId, exerciseId int64) error {
+	if _, err := service.ExerciseRepo.GetVisibleExercise(userId, exerciseId); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrExerciseNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (service *RecordService) ListRecords(userId int64, unit string) ([]models.PersonalRecord, error) {
+	unit, err := resolveWeightUnit(service.UserRepo, userId, unit)
+	if err != nil {
+		return nil, err
+	}
+	records, err := service.RecordRepo.ListRecords(userId)
+	if err != nil {
+		return nil, err
+	}
+	convertRecords(unit, records)
+	return records, nil
+}
+
+func (service *RecordService) ListExerciseRecords(userId, exerciseId int64, unit string) ([]models.PersonalRecord, error) {
+	if err := service.mustBeVisibleExercise(userId, exerciseId); err != nil {
+		return nil, err
+	}
+	unit, err := resolveWeightUnit(service.UserRepo, userId, unit)
+	if err != nil {
+		return nil, err
+	}
+	records, err := service.RecordRepo.ListRecordsForExercise(userId, exerciseId)
+	if err != nil {
+		return nil, err
+	}
+	convertRecords(unit, records)
+	return records, nil
+}
+
+func (service *RecordService) ListWorkoutRecords(userId, workoutId int64) ([]models.PersonalRecord, error) {
+	return service.RecordRepo.ListRecordsForWorkout(userId, workoutId)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func recordKey(r models.PersonalRecord) string {
+	if r.RecordType == models.RecordMaxReps && r.Weight != nil {
+		return r.RecordType + ":" + formatFloat(*r.Weight)
+	}
+	return r.RecordType
+}
+
+// entryLoad is the load a set moved; see effectiveLoad.
+func entryLoad(e models.SetHistoryEntry) *float64 {
+	if e.Load != nil {
+		return e.Load
+	}
+	return e.Weight
+}
+
+// computeRecords derives records from an exercise's sets. Max reps are kept
+// per logged weight; the other records use the load, so bodyweight counts
+// for bodyweight exercises. Weighted bodyweight exercises rank max weight by
+// the added weight alone.
+func computeRecords(formula string, entries []models.SetHistoryEntry) []models.PersonalRecord {
+	best := map[string]*models.PersonalRecord{}
+	var keys []string
+
+	consider := func(key string, candidate models.PersonalRecord) {
+		current, ok := best[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || candidate.Value > current.Value {
+			c := candidate
+			best[key] = &c
+		}
+	}
+
+	sessionVolume := map[int64]float64{}
+	var sessionOrder []int64
+	sessionDate := map[int64]string{}
+
+	for _, e := range entries {
+		if e.Reps == nil || *e.Reps <= 0 {
+			continue
+		}
+		reps := *e.Reps
+		weight, load := 0.0, 0.0
+		if e.Weight != nil {
+			weight = *e.Weight
+		}
+		if l := entryLoad(e); l != nil {
+			load = *l
+		}
+		setId := e.SetId
+		base := models.PersonalRecord{
+			Reps:       e.Reps,
+			Weight:     e.Weight,
+			SetId:      &setId,
+			WorkoutId:  e.WorkoutId,
+			AchievedAt: e.PerformedAt,
+		}
+
+		if _, ok := sessionDate[e.WorkoutId]; !ok {
+			sessionOrder = append(sessionOrder, e.WorkoutId)
+			sessionDate[e.WorkoutId] = e.PerformedAt
+		}
+		sessionVolume[e.WorkoutId] += float64(reps) * load
+
+		r := base
+		r.RecordType = models.RecordMaxReps
+		r.Value = float64(reps)
+		w := weight
+		r.Weight = &w
+		consider(recordKey(r), r)
+
+		maxWeight := load
+		if e.LoadType == models.LoadWeightedBodyweight {
+			maxWeight = weight
+		}
+		if maxWeight > 0 {
+			r = base
+			r.RecordType = models.RecordMaxWeight
+			r.Value = maxWeight
+			consider(r.RecordType, r)
+		}
+
+		if load <= 0 {
+			continue
+		}
+
+		r = base
+		r.RecordType = models.RecordBestE1RM
+		r.Value = estimateOneRepMax(formula, reps, load)
+		if r.Value > 0 {
+			consider(r.RecordType, r)
+		}
+
+		r = base
+		r.RecordType = models.RecordBestSetVolume
+		r.Value = roundTo(float64(reps)*load, 2)
+		consider(r.RecordType, r)
+	}
+
+	for _, workoutId := range sessionOrder {
+		if sessionVolume[workoutId] <= 0 {
+			continue
+		}
+		consider(models.RecordBestSessionVolume, models.PersonalRecord{
+			RecordType: models.RecordBestSessionVolume,
+			Value:      roundTo(sessionVolume[workoutId], 2),
+			WorkoutId:  workoutId,
+			AchievedAt: sessionDate[workoutId],
+		})
+	}
+
+	out := make([]models.PersonalRecord, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, *best[key])
+	}
+	return out
+}
+
+// RecalculateExercise rebuilds the stored records for one exercise and returns
+// the records that are new or better than before.
+func (service *RecordService) RecalculateExercise(userId, exerciseId int64) ([]models.PersonalRecord, error) {
+	previous, err := service.RecordRepo.ListRecordsForExercise(userId, exerciseId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := service.RecordRepo.ListSetsForExercise(userId, exerciseId)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs, err := service.UserRepo.GetPreferences(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.RecordRepo.ReplaceRecords(userId, exerciseId, computeRecords(prefs.E1RMFormula, entries)); err != nil {
+		return nil, err
+	}
+
+	current, err := service.RecordRepo.ListRecordsForExercise(userId, exerciseId)
+	if err != nil {
+		return nil, err
+	}
+
+	previousByKey := map[string]models.PersonalRecord{}
+	for _, r := range previous {
+		previousByKey[recordKey(r)] = r
+	}
+	var improved []models.PersonalRecord
+	for _, r := range current {
+		old, ok := previousByKey[recordKey(r)]
+		if !ok || r.Value > old.Value {
+			improved = append(improved, r)
+		}
+	}
+	return improved, nil
+}
+
+// Recalculate rebuilds the records of the given exercises, stopping at the
+// first failure.
+func (service *RecordService) Recalculate(userId int64, exerciseIds ...int64) error {
+	seen := map[int64]bool{}
+	for _, exerciseId := range exerciseIds {
+		if seen[exerciseId] {
+			continue
+		}
+		seen[exerciseId] = true
+		if _, err := service.RecalculateExercise(userId, exerciseId); err != nil {
+			return fmt.Errorf("recalculating records of exercise %d: %w", exerciseId, err)
+		}
+	}
+	return nil
+}
+
+func (service *RecordService) RecalculateAll(userId int64) error {
+	exerciseIds, err := service.RecordRepo.ListExerciseIdsForUser(userId)
+	if err != nil {
+		return err
+	}
+	return service.Recalculate(userId, exerciseIds...)
+}
+
+// RecalculateBodyweightExercises rebuilds the records of the user's
+// bodyweight-based exercises logged at or after since, e.g. after a weigh-in
+// changed. An empty since covers every workout.
+func (service *RecordService) RecalculateBodyweightExercises(userId int64, since string) error {
+	exerciseIds, err := service.RecordRepo.ListBodyweightExerciseIds(userId, since)
+	if err != nil {
+		return err
+	}
+	return service.Recalculate(userId, exerciseIds...)
+}
+
+// RecalculateExerciseForAll rebuilds one exercise's records for everyone who
+// logged it, e.g. after its load type changed.
+func (service *RecordService) RecalculateExerciseForAll(exerciseId int64) error {
+	userIds, err := service.RecordRepo.ListUserIdsForExercise(exerciseId)
+	if err != nil {
+		return err
+	}
+	for _, userId := range userIds {
+		if err := service.Recalculate(userId, exerciseId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *RecordService) GetE1RMHistory(userId, exerciseId int64, formula, unit, fromRaw, toRaw string) (models.E1RMSeries, error) {
+	if err := service.mustBeVisibleExercise(userId, exerciseId); err != nil {
+		return models.E1RMSeries{}, err
+	}
+	formula, err := service.ResolveFormula(userId, formula)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+	unit, err = resolveWeightUnit(service.UserRepo, userId, unit)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+	from, to, err := parseDateFilter(service.UserRepo, userId, fromRaw, toRaw)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+
+	entries, err := service.RecordRepo.ListSetsForExercise(userId, exerciseId)
+	if err != nil {
+		return models.E1RMSeries{}, err
+	}
+
+	series := models.E1RMSeries{ExerciseId: exerciseId, Formula: formula, WeightUnit: unit, Points: []models.E1RMPoint{}}
+	index := map[int64]int{}
+	for _, e := range entries {
+		if from != nil && e.PerformedAt < from.UTC().Format(time.RFC3339) {
+			continue
+		}
+		if to != nil && e.PerformedAt > to.UTC().Format(time.RFC3339) {
+			continue
+		}
+		load := entryLoad(e)
+		if e.Reps == nil || load == nil {
+			continue
+		}
+		e1rm := estimateOneRepMax(formula, *e.Reps, *load)
+		if e1rm <= 0 {
+			continue
+		}
+
+		point := models.E1RMPoint{
+			WorkoutId:   e.WorkoutId,
+			PerformedAt: e.PerformedAt,
+			E1RM:        e1rm,
+			Reps:        *e.Reps,
+			Weight:      *load,
+		}
+		i, ok := index[e.WorkoutId]
+		if !ok {
+			index[e.WorkoutId] = len(series.Points)
+			series.Points = append(series.Points, point)
+			continue
+		}
+		if e1rm > series.Points[i].E1RM {
+			series.Points[i] = point
+		}
+	}
+	for i := range series.Points {
+		series.Points[i].E1RM = fromKg(unit, series.Points[i].E1RM)
+		series.Points[i].Weight = fromKg(unit, series.Points[i].Weight)
+	}
+	return series, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func entry(setId, workoutId int64, performedAt string, reps int, weight, load *float64, loadType string) models.SetHistoryEntry {
+	return models.SetHistoryEntry{
+		SetId:       setId,
+		WorkoutId:   workoutId,
+		PerformedAt: performedAt,
+		Reps:        &reps,
+		Weight:      weight,
+		Load:        load,
+		LoadType:    loadType,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestComputeRecords(t *testing.T) {
+	type want struct {
+		value     float64
+		setId     int64 // 0 for session records
+		workoutId int64
+	}
+	tests := []struct {
+		name    string
+		entries []models.SetHistoryEntry
+		want    map[string]want
+	}{
+		{
+			name: "no sets",
+			want: map[string]want{},
+		},
+		{
+			name: "external load",
+			entries: []models.SetHistoryEntry{
+				entry(1, 1, "2026-01-01T10:00:00Z", 5, ptr(100.0), nil, models.LoadExternal),
+				entry(2, 1, "2026-01-01T10:00:00Z", 8, ptr(80.0), nil, models.LoadExternal),
+				entry(3, 2, "2026-01-08T10:00:00Z", 3, ptr(110.0), nil, models.LoadExternal),
+				entry(4, 2, "2026-01-08T10:00:00Z", 0, ptr(200.0), nil, models.LoadExternal),
+			},
+			want: map[string]want{
+				"max_reps:100":        {5, 1, 1},
+				"max_reps:80":         {8, 2, 1},
+				"max_reps:110":        {3, 3, 2},
+				"max_weight":          {110, 3, 2},
+				"best_e1rm":           {121, 3, 2},
+				"best_set_volume":     {640, 2, 1},
+				"best_session_volume": {1140, 0, 1},
+			},
+		},
+		{
+			name: "bodyweight counts the load",
+			entries: []models.SetHistoryEntry{
+				entry(1, 1, "2026-01-01T10:00:00Z", 10, nil, ptr(80.0), models.LoadBodyweight),
+			},
+			want: map[string]want{
+				"max_reps:0":          {10, 1, 1},
+				"max_weight":          {80, 1, 1},
+				"best_e1rm":           {106.67, 1, 1},
+				"best_set_volume":     {800, 1, 1},
+				"best_session_volume": {800, 0, 1},
+			},
+		},
+		{
+			name: "weighted bodyweight ranks max weight by the added weight",
+			entries: []models.SetHistoryEntry{
+				entry(1, 1, "2026-01-01T10:00:00Z", 5, ptr(20.0), ptr(100.0), models.LoadWeightedBodyweight),
+			},
+			want: map[string]want{
+				"max_reps:20":         {5, 1, 1},
+				"max_weight":          {20, 1, 1},
+				"best_e1rm":           {116.67, 1, 1},
+				"best_set_volume":     {500, 1, 1},
+				"best_session_volume": {500, 0, 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeRecords("epley", tt.entries)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for _, r := range got {
+				w, ok := tt.want[recordKey(r)]
+				if !ok {
+					t.Errorf("unexpected record %s", recordKey(r))
+					continue
+				}
+				var setId int64
+				if r.SetId != nil {
+					setId = *r.SetId
+				}
+				if r.Value != w.value || setId != w.setId || r.WorkoutId != w.workoutId {
+					t.Errorf("%s = %v (set %d, workout %d), want %v (set %d, workout %d)",
+						recordKey(r), r.Value, setId, r.WorkoutId, w.value, w.setId, w.workoutId)
+				}
+			}
+		})
+	}
+}
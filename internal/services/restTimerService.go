@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+// restEventBuffer is how many undelivered events a slow subscriber may have
+// before further ones are dropped for it.
+const restEventBuffer = 8
+
+// RestTimerService keeps the rest timers of live sessions and pushes their
+// changes to every device the user has subscribed with. Timers are stored,
+// so they survive a restart once Restore has been called.
+type RestTimerService struct {
+	RestTimerRepo *repo.RestTimerRepo
+	ExerciseRepo  *repo.ExerciseRepo
+
+	mu          sync.Mutex
+	timers      map[int64]*time.Timer
+	subscribers map[int64]map[chan models.RestTimerEvent]struct{}
+}
+
+func NewRestTimerService(rtr *repo.RestTimerRepo, er *repo.ExerciseRepo) *RestTimerService {
+	return &RestTimerService{
+		RestTimerRepo: rtr,
+		ExerciseRepo:  er,
+		timers:        map[int64]*time.Timer{},
+		subscribers:   map[int64]map[chan models.RestTimerEvent]struct{}{},
+	}
+}
+
+func (service *RestTimerService) ListRestDefaults(userId int64) ([]models.RestDefault, error) {
+	return service.RestTimerRepo.ListDefaults(userId)
+}
+
+func (service *RestTimerService) SetRestDefault(userId, exerciseId int64, restSeconds int) (models.RestDefault, error) {
+	if _, err := service.ExerciseRepo.GetVisibleExercise(userId, exerciseId); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return models.RestDefault{}, ErrExerciseNotFound
+		}
+		return models.RestDefault{}, err
+	}
+	if restSeconds < 1 || restSeconds > maxRestSeconds {
+		return models.RestDefault{}, &FieldError{Field: "restSeconds", Message: fmt.Sprintf("must be between 1 and %d", maxRestSeconds)}
+	}
+	return service.RestTimerRepo.SetDefault(userId, exerciseId, restSeconds)
+}
+
+func (service *RestTimerService) DeleteRestDefault(userId, exerciseId int64) error {
+	return service.RestTimerRepo.DeleteDefault(userId, exerciseId)
+}
+
+// restSecondsAfter is the rest following a set: its own restSeconds, else
+// the user's default for the exercise, else none.
+func (service *RestTimerService) restSecondsAfter(userId, exerciseId int64, set models.Set) (int, error) {
+	if set.RestSeconds != nil {
+		return *set.RestSeconds, nil
+	}
+	d, err := service.RestTimerRepo.GetDefault(userId, exerciseId)
+	if errors.Is(err, repo.ErrNotFound) {
+		return 0, nil
+	}
+	return d.RestSeconds, err
+}
+
+func withRemaining(t models.RestTimer, now time.Time) models.RestTimer {
+	endsAt, err := time.Parse(time.RFC3339, t.EndsAt)
+	if err != nil {
+		return t
+	}
+	t.RemainingSeconds = max(0, int(math.Ceil(endsAt.Sub(now).Seconds())))
+	return t
+}
+
+// StartAfterSet starts the rest following a completed set, replacing any
+// running timer. It returns nil when the set has no rest.
+func (service *RestTimerService) StartAfterSet(userId, workoutId, exerciseId int64, set models.Set) (*models.RestTimer, error) {
+	seconds, err := service.restSecondsAfter(userId, exerciseId, set)
+	if err != nil {
+		return nil, err
+	}
+	if seconds <= 0 {
+		return nil, service.cancel(userId, func(models.RestTimer) bool { return true })
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	t := models.RestTimer{
+		WorkoutId:       workoutId,
+		SetId:           set.Id,
+		DurationSeconds: seconds,
+		StartedAt:       now.Format(time.RFC3339),
+		EndsAt:          now.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339),
+	}
+	if err := service.RestTimerRepo.Start(userId, t); err != nil {
+		return nil, err
+	}
+	service.schedule(userId, t)
+
+	t = withRemaining(t, now)
+	service.publish(userId, models.RestTimerEvent{Type: models.RestStarted, Timer: t})
+	return &t, nil
+}
+
+// GetRestTimer returns the user's running timer; ErrNotFound when resting
+// is over or never started.
+func (service *RestTimerService) GetRestTimer(userId int64) (models.RestTimer, error) {
+	t, err := service.RestTimerRepo.Get(userId)
+	if err != nil {
+		return models.RestTimer{}, err
+	}
+	t = withRemaining(t, time.Now())
+	if t.RemainingSeconds == 0 {
+		return models.RestTimer{}, repo.ErrNotFound
+	}
+	return t, nil
+}
+
+// SkipRest ends the running timer early.
+func (service *RestTimerService) SkipRest(userId int64) error {
+	if _, err := service.GetRestTimer(userId); err != nil {
+		return err
+	}
+	return service.cancel(userId, func(models.RestTimer) bool { return true })
+}
+
+func (service *RestTimerService) CancelForSet(userId, setId int64) error {
+	return service.cancel(userId, func(t models.RestTimer) bool { return t.SetId == setId })
+}
+
+func (service *RestTimerService) CancelForWorkout(userId, workoutId int64) error {
+	return service.cancel(userId, func(t models.RestTimer) bool { return t.WorkoutId == workoutId })
+}
+
+// cancel stops the user's running timer if it matches and tells the user's
+// devices about it.
+func (service *RestTimerService) cancel(userId int64, matches func(models.RestTimer) bool) error {
+	t, err := service.RestTimerRepo.Get(userId)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !matches(t) {
+		return nil
+	}
+
+	if err := service.RestTimerRepo.Delete(userId, t.SetId, t.StartedAt); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	service.mu.Lock()
+	if timer, ok := service.timers[userId]; ok {
+		timer.Stop()
+		delete(service.timers, userId)
+	}
+	service.mu.Unlock()
+
+	t = withRemaining(t, time.Now())
+	if t.RemainingSeconds > 0 {
+		service.publish(userId, models.RestTimerEvent{Type: models.RestCancelled, Timer: t})
+	}
+	return nil
+}
+
+// schedule arranges for the rest_ended event of t, replacing the user's
+// previous schedule.
+func (service *RestTimerService) schedule(userId int64, t models.RestTimer) {
+	endsAt, err := time.Parse(time.RFC3339, t.EndsAt)
+	if err != nil {
+		log.Printf("[RestTimer] invalid end user=%d set=%d: %v", userId, t.SetId, err)
+		return
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if previous, ok := service.timers[userId]; ok {
+		previous.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(endsAt), func() {
+		service.mu.Lock()
+		if service.timers[userId] == timer {
+			delete(service.timers, userId)
+		}
+		service.mu.Unlock()
+
+		if err := service.RestTimerRepo.Delete(userId, t.SetId, t.StartedAt); err != nil {
+			if !errors.Is(err, repo.ErrNotFound) {
+				log.Printf("[RestTimer] end failed user=%d set=%d: %v", userId, t.SetId, err)
+			}
+			return
+		}
+		ended := t
+		ended.RemainingSeconds = 0
+		service.publish(userId, models.RestTimerEvent{Type: models.RestEnded, Timer: ended})
+	})
+	service.timers[userId] = timer
+}
+
+// Restore schedules the stored timers, e.g. after a restart. Timers that
+// ran out meanwhile are cleared right away.
+func (service *RestTimerService) Restore() error {
+	timers, err := service.RestTimerRepo.ListAll()
+	if err != nil {
+		return err
+	}
+	for userId, t := range timers {
+		service.schedule(userId, t)
+	}
+	return nil
+}
+
+// Subscribe registers a device of the user for rest timer events. The
+// returned function unsubscribes it.
+func (service *RestTimerService) Subscribe(userId int64) (<-chan models.RestTimerEvent, func()) {
+	ch := make(chan models.RestTimerEvent, restEventBuffer)
+
+	service.mu.Lock()
+	if service.subscribers[userId] == nil {
+		service.subscribers[userId] = map[chan models.RestTimerEvent]struct{}{}
+	}
+	service.subscribers[userId][ch] = struct{}{}
+	service.mu.Unlock()
+
+	return ch, func() {
+		service.mu.Lock()
+		defer service.mu.Unlock()
+		delete(service.subscribers[userId], ch)
+		if len(service.subscribers[userId]) == 0 {
+			delete(service.subscribers, userId)
+		}
+	}
+}
+
+func (service *RestTimerService) publish(userId int64, event models.RestTimerEvent) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	for ch := range service.subscribers[userId] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("[RestTimer] dropped %s event for slow subscriber user=%d", event.Type, userId)
+		}
+	}
+}
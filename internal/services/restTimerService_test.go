@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestRestTimerEvents(t *testing.T) {
+	service := NewRestTimerService(nil, nil)
+
+	phone, unsubscribePhone := service.Subscribe(1)
+	watch, unsubscribeWatch := service.Subscribe(1)
+	other, unsubscribeOther := service.Subscribe(2)
+	defer unsubscribeOther()
+
+	event := models.RestTimerEvent{Type: models.RestStarted, Timer: models.RestTimer{SetId: 5}}
+	service.publish(1, event)
+
+	for name, ch := range map[string]<-chan models.RestTimerEvent{"phone": phone, "watch": watch} {
+		select {
+		case got := <-ch:
+			if got.Type != event.Type || got.Timer.SetId != event.Timer.SetId {
+				t.Errorf("%s got %+v, want %+v", name, got, event)
+			}
+		default:
+			t.Errorf("%s got no event", name)
+		}
+	}
+	select {
+	case got := <-other:
+		t.Errorf("another user's device got %+v", got)
+	default:
+	}
+
+	t.Run("slow subscriber drops events instead of blocking", func(t *testing.T) {
+		for range restEventBuffer + 3 {
+			service.publish(1, event)
+		}
+		if len(phone) != restEventBuffer {
+			t.Errorf("buffered %d events, want %d", len(phone), restEventBuffer)
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		unsubscribePhone()
+		unsubscribeWatch()
+		service.mu.Lock()
+		_, ok := service.subscribers[1]
+		service.mu.Unlock()
+		if ok {
+			t.Error("user 1 still has subscribers")
+		}
+		service.publish(1, event) // must not panic or block
+	})
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+type RoutineService struct {
+	RoutineRepo    *repo.RoutineRepo
+	ExerciseRepo   *repo.ExerciseRepo
+	WorkoutService *WorkoutService
+	RecordService  *RecordService
+}
+
+func NewRoutineService(rr *repo.RoutineRepo, er *repo.ExerciseRepo, ws *WorkoutService, rs *RecordService) *RoutineService {
+	return &RoutineService{
+		RoutineRepo:    rr,
+		ExerciseRepo:   er,
+		WorkoutService: ws,
+		RecordService:  rs,
+	}
+}
+
+func (service *RoutineService) weightUnit(userId int64) (string, error) {
+	return resolveWeightUnit(service.RecordService.UserRepo, userId, "")
+}
+
+func (service *RoutineService) validateRoutine(userId int64, unit string, routine *models.Routine) error {
+	routine.Name = strings.TrimSpace(routine.Name)
+	if routine.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	for i, ex := range routine.Exercises {
+		if ex.ExerciseOrder <= 0 {
+			return fmt.Errorf("exercises[%d]: exerciseOrder must be >= 1", i)
+		}
+		exercise, err := service.ExerciseRepo.GetVisibleExercise(userId, ex.ExerciseId)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return fmt.Errorf("exercises[%d]: %w", i, ErrExerciseNotFound)
+			}
+			return err
+		}
+		seen := map[int]bool{}
+		for j := range ex.Sets {
+			set := &ex.Sets[j]
+			input := models.Set{Weight: set.Weight, Unit: set.Unit}
+			if _, err := setInputToKg(unit, &input); err != nil {
+				return fmt.Errorf("exercises[%d].sets[%d]: %w", i, j, err)
+			}
+			set.Weight, set.Unit = input.Weight, ""
+			if set.SetNumber <= 0 {
+				return fmt.Errorf("exercises[%d].sets[%d]: setNumber must be >= 1", i, j)
+			}
+			if seen[set.SetNumber] {
+				return fmt.Errorf("exercises[%d].sets[%d]: duplicate setNumber %d", i, j, set.SetNumber)
+			}
+			seen[set.SetNumber] = true
+			if err := rejectUntrackedMetrics(exercise.MetricType, models.Set{Reps: set.Reps, Weight: set.Weight}); err != nil {
+				return fmt.Errorf("exercises[%d].sets[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	labels, err := validateGroups(routine.Groups)
+	if err != nil {
+		return err
+	}
+	for i := range routine.Exercises {
+		ex := &routine.Exercises[i]
+		if ex.GroupLabel, err = memberGroupLabel(labels, i, ex.GroupLabel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *RoutineService) CreateRoutine(userId int64, routine models.Routine) (models.Routine, error) {
+	unit, err := service.weightUnit(userId)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	if err := service.validateRoutine(userId, unit, &routine); err != nil {
+		return models.Routine{}, err
+	}
+	created, err := service.RoutineRepo.CreateRoutine(userId, routine)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	convertRoutine(unit, &created)
+	return created, nil
+}
+
+func (service *RoutineService) GetRoutine(userId, routineId int64) (models.Routine, error) {
+	unit, err := service.weightUnit(userId)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	routine, err := service.RoutineRepo.GetRoutineById(userId, routineId)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	convertRoutine(unit, &routine)
+	return routine, nil
+}
+
+func (service *RoutineService) ListRoutines(userId int64) ([]models.Routine, error) {
+	unit, err := service.weightUnit(userId)
+	if err != nil {
+		return nil, err
+	}
+	routines, err := service.RoutineRepo.ListRoutines(userId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range routines {
+		convertRoutine(unit, &routines[i])
+	}
+	return routines, nil
+}
+
+func (service *RoutineService) UpdateRoutine(userId, routineId int64, routine models.Routine) (models.Routine, error) {
+	unit, err := service.weightUnit(userId)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	if err := service.validateRoutine(userId, unit, &routine); err != nil {
+		return models.Routine{}, err
+	}
+	updated, err := service.RoutineRepo.UpdateRoutine(userId, routineId, routine)
+	if err != nil {
+		return models.Routine{}, err
+	}
+	convertRoutine(unit, &updated)
+	return updated, nil
+}
+
+func (service *RoutineService) DeleteRoutine(userId, routineId int64) error {
+	return service.RoutineRepo.DeleteRoutine(userId, routineId)
+}
+
+// StartRoutine opens a live session pre-filled with the routine's groups,
+// exercises and planned sets, none of them ticked off yet. It fails with
+// ErrWorkoutInProgress while another session is open.
+func (service *RoutineService) StartRoutine(userId, routineId int64, timezone *string) (models.WorkoutWithDetails, error) {
+	routine, err := service.RoutineRepo.GetRoutineById(userId, routineId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	details := models.WorkoutWithDetails{
+		Workout: models.Workout{
+			PerformedAt: localNow(timezone),
+			Timezone:    timezone,
+			Notes:       routine.Notes,
+			RoutineId:   &routine.Id,
+		},
+		Groups: routine.Groups,
+	}
+	for _, re := range routine.Exercises {
+		ex := models.WorkoutExerciseWithSets{
+			WorkoutExercise: models.WorkoutExercise{
+				ExerciseId:    re.ExerciseId,
+				ExerciseOrder: re.ExerciseOrder,
+				Notes:         re.Notes,
+				GroupLabel:    re.GroupLabel,
+			},
+		}
+		for _, rs := range re.Sets {
+			ex.Sets = append(ex.Sets, models.Set{
+				SetNumber: rs.SetNumber,
+				SetType:   models.SetTypeNormal,
+				Reps:      rs.Reps,
+				Weight:    rs.Weight,
+			})
+		}
+		details.Exercises = append(details.Exercises, ex)
+	}
+
+	workoutId, err := service.WorkoutService.startSession(userId, details)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	return service.WorkoutService.GetWorkoutDetails(userId, workoutId, "", "")
+}
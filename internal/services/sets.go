@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"workout-tracker/internal/models"
+)
+
+const maxRestSeconds = 3600
+
+// metricFields lists the measurement fields each exercise metric type
+// accepts on its sets.
+var metricFields = map[string][]string{
+	models.MetricWeightReps:   {"reps", "weight"},
+	models.MetricReps:         {"reps"},
+	models.MetricTime:         {"durationSeconds", "calories", "avgHeartRate", "maxHeartRate"},
+	models.MetricDistanceTime: {"distanceMeters", "durationSeconds", "calories", "avgHeartRate", "maxHeartRate"},
+}
+
+type setMetric struct {
+	name    string
+	present bool
+}
+
+func setMetrics(set models.Set) []setMetric {
+	return []setMetric{
+		{"reps", set.Reps != nil},
+		{"weight", set.Weight != nil},
+		{"durationSeconds", set.DurationSeconds != nil},
+		{"distanceMeters", set.DistanceMeters != nil},
+		{"calories", set.Calories != nil},
+		{"avgHeartRate", set.AvgHeartRate != nil},
+		{"maxHeartRate", set.MaxHeartRate != nil},
+	}
+}
+
+// requiredMetricFields lists the measurements a logged set of each metric
+// type must have.
+var requiredMetricFields = map[string][]string{
+	models.MetricWeightReps:   {"reps"},
+	models.MetricReps:         {"reps"},
+	models.MetricTime:         {"durationSeconds"},
+	models.MetricDistanceTime: {"distanceMeters", "durationSeconds"},
+}
+
+// rejectUntrackedMetrics rejects measurements the exercise does not track.
+func rejectUntrackedMetrics(metricType string, set models.Set) error {
+	allowed := metricFields[metricType]
+	for _, m := range setMetrics(set) {
+		if m.present && !slices.Contains(allowed, m.name) {
+			return fmt.Errorf("%s is not tracked for %s exercises", m.name, metricType)
+		}
+	}
+	return nil
+}
+
+// validateSetMetrics checks a logged set against the exercise: no untracked
+// measurements and all the ones its metric type needs.
+func validateSetMetrics(metricType string, set models.Set) error {
+	if err := rejectUntrackedMetrics(metricType, set); err != nil {
+		return err
+	}
+	for _, m := range setMetrics(set) {
+		if !m.present && slices.Contains(requiredMetricFields[metricType], m.name) {
+			return fmt.Errorf("%s is required for %s exercises", m.name, metricType)
+		}
+	}
+	return nil
+}
+
+var tempoPattern = regexp.MustCompile(`^(?:([0-9X])([0-9X])([0-9X])([0-9X])|([0-9]{1,2}|X)-([0-9]{1,2}|X)-([0-9]{1,2}|X)-([0-9]{1,2}|X))$`)
+
+// normalizeTempo accepts "31X0" or "3-1-X-0" and returns the dashed form.
+func normalizeTempo(tempo string) (string, error) {
+	m := tempoPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(tempo)))
+	if m == nil {
+		return "", fmt.Errorf("tempo must be four phases of seconds or X, e.g. 3-1-X-0")
+	}
+	phases := m[1:5]
+	if phases[0] == "" {
+		phases = m[5:9]
+	}
+	return strings.Join(phases, "-"), nil
+}
+
+func validateSet(set *models.Set) error {
+	if set.SetNumber <= 0 {
+		return fmt.Errorf("setNumber must be >= 1")
+	}
+	set.SetType = strings.ToLower(strings.TrimSpace(set.SetType))
+	if set.SetType == "" {
+		set.SetType = models.SetTypeNormal
+	}
+	if !slices.Contains(models.SetTypes, set.SetType) {
+		return fmt.Errorf("setType must be one of %s", strings.Join(models.SetTypes, ", "))
+	}
+	if set.RPE != nil {
+		if *set.RPE < 6 || *set.RPE > 10 || math.Mod(*set.RPE*2, 1) != 0 {
+			return fmt.Errorf("rpe must be between 6 and 10 in steps of 0.5")
+		}
+	}
+	if set.RIR != nil && (*set.RIR < 0 || *set.RIR > 10) {
+		return fmt.Errorf("rir must be between 0 and 10")
+	}
+	if set.Tempo != nil {
+		if strings.TrimSpace(*set.Tempo) == "" {
+			set.Tempo = nil
+		} else {
+			tempo, err := normalizeTempo(*set.Tempo)
+			if err != nil {
+				return err
+			}
+			set.Tempo = &tempo
+		}
+	}
+	if set.RestSeconds != nil && (*set.RestSeconds < 0 || *set.RestSeconds > maxRestSeconds) {
+		return fmt.Errorf("restSeconds must be between 0 and %d", maxRestSeconds)
+	}
+	if set.DurationSeconds != nil && *set.DurationSeconds < 0 {
+		return fmt.Errorf("durationSeconds must be >= 0")
+	}
+	if set.DistanceMeters != nil && *set.DistanceMeters < 0 {
+		return fmt.Errorf("distanceMeters must be >= 0")
+	}
+	if set.Calories != nil && *set.Calories < 0 {
+		return fmt.Errorf("calories must be >= 0")
+	}
+	if set.AvgHeartRate != nil && (*set.AvgHeartRate < 20 || *set.AvgHeartRate > 250) {
+		return fmt.Errorf("avgHeartRate must be between 20 and 250")
+	}
+	if set.MaxHeartRate != nil && (*set.MaxHeartRate < 20 || *set.MaxHeartRate > 250) {
+		return fmt.Errorf("maxHeartRate must be between 20 and 250")
+	}
+	if set.AvgHeartRate != nil && set.MaxHeartRate != nil && *set.AvgHeartRate > *set.MaxHeartRate {
+		return fmt.Errorf("avgHeartRate must not exceed maxHeartRate")
+	}
+	return nil
+}
+
+// effectiveLoad is the weight a set moved: the bodyweight based load for
+// bodyweight exercises, the logged weight otherwise.
+func effectiveLoad(set models.Set) *float64 {
+	if set.Load != nil {
+		return set.Load
+	}
+	return set.Weight
+}
+
+func setChanged(a, b models.Set) bool {
+	return a.SetNumber != b.SetNumber ||
+		a.SetType != b.SetType ||
+		!equalPtr(a.Reps, b.Reps) ||
+		!sameWeight(a.Weight, b.Weight) ||
+		!equalPtr(a.RPE, b.RPE) ||
+		!equalPtr(a.RIR, b.RIR) ||
+		!equalPtr(a.Tempo, b.Tempo) ||
+		!equalPtr(a.RestSeconds, b.RestSeconds) ||
+		!equalPtr(a.DurationSeconds, b.DurationSeconds) ||
+		!equalPtr(a.DistanceMeters, b.DistanceMeters) ||
+		!equalPtr(a.Calories, b.Calories) ||
+		!equalPtr(a.AvgHeartRate, b.AvgHeartRate) ||
+		!equalPtr(a.MaxHeartRate, b.MaxHeartRate)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"workout-tracker/internal/models"
+	"workout-tracker/internal/repo"
+)
+
+// Weights are stored in kilograms and converted at the service boundary.
+const kgPerLb = 0.45359237
+
+func validateWeightUnit(unit string) error {
+	if !slices.Contains(models.WeightUnits, unit) {
+		return fmt.Errorf("unit must be one of %s", strings.Join(models.WeightUnits, ", "))
+	}
+	return nil
+}
+
+// resolveWeightUnit returns the requested unit, falling back to the user's
+// preference when none is given.
+func resolveWeightUnit(users *repo.UserRepo, userId int64, unit string) (string, error) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		prefs, err := users.GetPreferences(userId)
+		if err != nil {
+			return "", err
+		}
+		unit = prefs.WeightUnit
+	}
+	if err := validateWeightUnit(unit); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidQuery, err)
+	}
+	return unit, nil
+}
+
+func toKg(unit string, weight *float64) *float64 {
+	if weight == nil || unit != models.UnitLb {
+		return weight
+	}
+	kg := *weight * kgPerLb
+	return &kg
+}
+
+func fromKg(unit string, kg float64) float64 {
+	if unit == models.UnitLb {
+		kg /= kgPerLb
+	}
+	return roundTo(kg, 2)
+}
+
+// weightRoundingKg is below the smallest step weights are shown in (0.01 kg
+// or 0.01 lb), so weights closer than this display the same.
+const weightRoundingKg = 0.004
+
+// sameWeight compares stored kg weights the way a user sees them, so a
+// weight that went through a rounded lb round trip still counts as equal.
+func sameWeight(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return math.Abs(*a-*b) < weightRoundingKg
+}
+
+// keepStoredWeight returns the stored weight when the input only differs by
+// display rounding, so sending a set back unchanged doesn't rewrite it.
+func keepStoredWeight(stored, input *float64) *float64 {
+	if sameWeight(stored, input) {
+		return stored
+	}
+	return input
+}
+
+func fromKgPtr(unit string, kg *float64) *float64 {
+	if kg == nil {
+		return nil
+	}
+	v := fromKg(unit, *kg)
+	return &v
+}
+
+// setInputToKg converts a set's weight from the unit it was entered in,
+// defaulting to the user's unit, and returns the unit used.
+func setInputToKg(defaultUnit string, set *models.Set) (string, error) {
+	unit := strings.ToLower(strings.TrimSpace(set.Unit))
+	if unit == "" {
+		unit = defaultUnit
+	}
+	if err := validateWeightUnit(unit); err != nil {
+		return "", err
+	}
+	set.Weight = toKg(unit, set.Weight)
+	set.Unit = ""
+	return unit, nil
+}
+
+func convertSet(unit string, set *models.Set) {
+	set.Weight = fromKgPtr(unit, set.Weight)
+	set.Load = fromKgPtr(unit, set.Load)
+	set.E1RM = fromKgPtr(unit, set.E1RM)
+	if set.Weight != nil || set.Load != nil || set.E1RM != nil {
+		set.Unit = unit
+	}
+	convertRecords(unit, set.NewRecords)
+}
+
+func convertRecords(unit string, records []models.PersonalRecord) {
+	for i := range records {
+		r := &records[i]
+		r.Weight = fromKgPtr(unit, r.Weight)
+		if r.RecordType != models.RecordMaxReps {
+			r.Value = fromKg(unit, r.Value)
+		}
+		r.Unit = unit
+	}
+}
+
+func convertDetails(unit string, details *models.WorkoutWithDetails) {
+	for i := range details.Exercises {
+		for j := range details.Exercises[i].Sets {
+			convertSet(unit, &details.Exercises[i].Sets[j])
+		}
+	}
+}
+
+func convertRoutine(unit string, routine *models.Routine) {
+	for i := range routine.Exercises {
+		for j := range routine.Exercises[i].Sets {
+			set := &routine.Exercises[i].Sets[j]
+			set.Weight = fromKgPtr(unit, set.Weight)
+			if set.Weight != nil {
+				set.Unit = unit
+			}
+		}
+	}
+}
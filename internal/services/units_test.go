@@ -0,0 +1,83 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestToKg(t *testing.T) {
+	tests := []struct {
+		name   string
+		unit   string
+		weight *float64
+		want   *float64
+	}{
+		{"nil stays nil", models.UnitLb, nil, nil},
+		{"kg is unchanged", models.UnitKg, ptr(100.0), ptr(100.0)},
+		{"lb", models.UnitLb, ptr(220.46), ptr(99.9989738902)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toKg(tt.unit, tt.weight)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("toKg(%q, %v) = %v, want %v", tt.unit, tt.weight, got, tt.want)
+			}
+			if got != nil && math.Abs(*got-*tt.want) > 1e-9 {
+				t.Errorf("toKg(%q, %v) = %v, want %v", tt.unit, *tt.weight, *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestFromKg(t *testing.T) {
+	tests := []struct {
+		name string
+		unit string
+		kg   float64
+		want float64
+	}{
+		{"kg rounds to 0.01", models.UnitKg, 100.004, 100},
+		{"lb", models.UnitLb, 100, 220.46},
+		{"lb round trip", models.UnitLb, 99.9989738902, 220.46},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fromKg(tt.unit, tt.kg); got != tt.want {
+				t.Errorf("fromKg(%q, %v) = %v, want %v", tt.unit, tt.kg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameWeight(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *float64
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"one nil", ptr(100.0), nil, false},
+		{"equal", ptr(100.0), ptr(100.0), true},
+		{"lb round trip", ptr(100.0), toKg(models.UnitLb, ptr(fromKg(models.UnitLb, 100))), true},
+		{"one display step apart", ptr(100.0), ptr(100.01), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameWeight(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameWeight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeepStoredWeight(t *testing.T) {
+	stored := ptr(100.0)
+	if got := keepStoredWeight(stored, ptr(99.999)); got != stored {
+		t.Errorf("rounding difference: got %v, want the stored pointer", *got)
+	}
+	input := ptr(102.5)
+	if got := keepStoredWeight(stored, input); got != input {
+		t.Errorf("real change: got %v, want the input pointer", *got)
+	}
+}
@@ -27,11 +27,12 @@ var ErrMissingRefreshToken = errors.New("missing refresh token")
 var ErrInvalidRefreshToken = errors.New("invalid refresh token")
 
 type UserService struct {
-	Repo *repo.UserRepo
+	Repo          *repo.UserRepo
+	RecordService *RecordService
 }
 
-func NewUserService(repo *repo.UserRepo) *UserService {
-	return &UserService{Repo: repo}
+func NewUserService(repo *repo.UserRepo, rs *RecordService) *UserService {
+	return &UserService{Repo: repo, RecordService: rs}
 }
 
 func (service *UserService) getSecretKey() ([]byte, error) {
@@ -107,6 +108,56 @@ func (service *UserService) VerifyToken(tokenString string) (jwt.MapClaims, erro
 	return claims, nil
 }
 
+func (service *UserService) IsAdmin(userId int64) (bool, error) {
+	return service.Repo.IsAdmin(userId)
+}
+
+func (service *UserService) GetPreferences(userId int64) (models.UserPreferences, error) {
+	return service.Repo.GetPreferences(userId)
+}
+
+func (service *UserService) UpdatePreferences(userId int64, prefs models.UserPreferences) (models.UserPreferences, error) {
+	current, err := service.Repo.GetPreferences(userId)
+	if err != nil {
+		return models.UserPreferences{}, err
+	}
+
+	prefs.E1RMFormula = strings.ToLower(strings.TrimSpace(prefs.E1RMFormula))
+	if prefs.E1RMFormula == "" {
+		prefs.E1RMFormula = current.E1RMFormula
+	}
+	if err := validateE1RMFormula(prefs.E1RMFormula); err != nil {
+		return models.UserPreferences{}, err
+	}
+
+	prefs.Timezone = strings.TrimSpace(prefs.Timezone)
+	if prefs.Timezone == "" {
+		prefs.Timezone = current.Timezone
+	}
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+		return models.UserPreferences{}, fmt.Errorf("timezone must be an IANA zone name like Europe/Berlin")
+	}
+
+	prefs.WeightUnit = strings.ToLower(strings.TrimSpace(prefs.WeightUnit))
+	if prefs.WeightUnit == "" {
+		prefs.WeightUnit = current.WeightUnit
+	}
+	if err := validateWeightUnit(prefs.WeightUnit); err != nil {
+		return models.UserPreferences{}, err
+	}
+
+	updated, err := service.Repo.UpdatePreferences(userId, prefs)
+	if err != nil {
+		return models.UserPreferences{}, err
+	}
+	if updated.E1RMFormula != current.E1RMFormula {
+		if err := service.RecordService.RecalculateAll(userId); err != nil {
+			return models.UserPreferences{}, err
+		}
+	}
+	return updated, nil
+}
+
 func (service *UserService) Login(ctx context.Context, name, password string) (token string, rawToken string, expiresAt time.Time, err error) { // returns jwt as string
 	if err := service.validateCredentials(name, password); err != nil {
 		return "", "", time.Time{}, err
@@ -3,77 +3,761 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"slices"
 	"strings"
+	"time"
 	"workout-tracker/internal/models"
 	"workout-tracker/internal/repo"
 )
 
 var ErrForbidden = errors.New("forbidden")
+var ErrExerciseNotFound = errors.New("exercise not found")
+var ErrWorkoutInProgress = errors.New("another workout is already in progress")
+var ErrWorkoutNotInProgress = errors.New("workout is not in progress")
+
+// staleSessionAfter is how long a live session may go without activity
+// before it is abandoned.
+const staleSessionAfter = 12 * time.Hour
 
 type WorkoutService struct {
 	WorkoutRepo         *repo.WorkoutRepo
 	WorkoutExerciseRepo *repo.WorkoutExerciseRepo
+	WorkoutGroupRepo    *repo.WorkoutGroupRepo
 	SetRepo             *repo.SetRepo
+	ExerciseRepo        *repo.ExerciseRepo
+	RecordService       *RecordService
+	RestTimerService    *RestTimerService
 }
 
-func NewWorkoutService(wr *repo.WorkoutRepo, wer *repo.WorkoutExerciseRepo, sr *repo.SetRepo) *WorkoutService {
+func NewWorkoutService(wr *repo.WorkoutRepo, wer *repo.WorkoutExerciseRepo, wgr *repo.WorkoutGroupRepo, sr *repo.SetRepo, er *repo.ExerciseRepo, rs *RecordService, rts *RestTimerService) *WorkoutService {
 	return &WorkoutService{
 		WorkoutRepo:         wr,
 		WorkoutExerciseRepo: wer,
+		WorkoutGroupRepo:    wgr,
 		SetRepo:             sr,
+		ExerciseRepo:        er,
+		RecordService:       rs,
+		RestTimerService:    rts,
+	}
+}
+
+func exerciseIdsOf(exercises []models.WorkoutExerciseWithSets) []int64 {
+	ids := make([]int64, 0, len(exercises))
+	for _, ex := range exercises {
+		ids = append(ids, ex.ExerciseId)
+	}
+	return ids
+}
+
+// validateWorkoutGroups checks groups and that every exercise's groupLabel
+// names one of them.
+func validateWorkoutGroups(groups []models.ExerciseGroup, exercises []models.WorkoutExerciseWithSets) error {
+	labels, err := validateGroups(groups)
+	if err != nil {
+		return err
+	}
+	for i := range exercises {
+		ex := &exercises[i]
+		if ex.GroupLabel, err = memberGroupLabel(labels, i, ex.GroupLabel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordsForSet rebuilds the exercise's records after set was written and
+// attaches the ones it set.
+func (service *WorkoutService) recordsForSet(userId int64, we models.WorkoutExercise, set *models.Set) error {
+	improved, err := service.RecordService.RecalculateExercise(userId, we.ExerciseId)
+	if err != nil {
+		return fmt.Errorf("recalculating records of exercise %d: %w", we.ExerciseId, err)
+	}
+	for _, r := range improved {
+		if (r.SetId != nil && *r.SetId == set.Id) || (r.SetId == nil && r.WorkoutId == we.WorkoutId) {
+			set.NewRecords = append(set.NewRecords, r)
+		}
+	}
+	return nil
+}
+
+func (service *WorkoutService) weightUnit(userId int64, unit string) (string, error) {
+	return resolveWeightUnit(service.RecordService.UserRepo, userId, unit)
+}
+
+func (service *WorkoutService) mustBeVisibleExercise(userId, exerciseId int64) (models.Exercise, error) {
+	ex, err := service.ExerciseRepo.GetVisibleExercise(userId, exerciseId)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return models.Exercise{}, ErrExerciseNotFound
+		}
+		return models.Exercise{}, err
+	}
+	return ex, nil
+}
+
+// prepareSet converts a single set's weight to kg and validates it against
+// the exercise. It returns the unit the weight was entered in.
+func (service *WorkoutService) prepareSet(userId, exerciseId int64, set *models.Set) (string, error) {
+	pref, err := service.weightUnit(userId, "")
+	if err != nil {
+		return "", err
+	}
+	unit, err := setInputToKg(pref, set)
+	if err != nil {
+		return "", err
+	}
+	if err := validateSet(set); err != nil {
+		return "", err
+	}
+	ex, err := service.ExerciseRepo.GetExerciseById(exerciseId)
+	if err != nil {
+		return "", err
+	}
+	return unit, validateSetMetrics(ex.MetricType, *set)
+}
+
+// assignMissingNumbers gives every omitted (zero) number the first free one
+// after the entry before it, so [_, 3, _] becomes [1, 3, 4].
+func assignMissingNumbers(numbers []*int) {
+	used := map[int]bool{}
+	for _, n := range numbers {
+		used[*n] = true
+	}
+	prev := 0
+	for _, n := range numbers {
+		if *n == 0 {
+			next := prev + 1
+			for used[next] {
+				next++
+			}
+			*n = next
+			used[next] = true
+		}
+		prev = *n
+	}
+}
+
+func (service *WorkoutService) validateWorkoutExercises(userId int64, unit string, exercises []models.WorkoutExerciseWithSets) error {
+	orders := make([]*int, 0, len(exercises))
+	for i := range exercises {
+		orders = append(orders, &exercises[i].ExerciseOrder)
+		setNumbers := make([]*int, 0, len(exercises[i].Sets))
+		for j := range exercises[i].Sets {
+			setNumbers = append(setNumbers, &exercises[i].Sets[j].SetNumber)
+		}
+		assignMissingNumbers(setNumbers)
+	}
+	assignMissingNumbers(orders)
+
+	seenOrders := map[int]bool{}
+	for i, ex := range exercises {
+		if ex.ExerciseOrder <= 0 {
+			return fmt.Errorf("exercises[%d]: exerciseOrder must be >= 1", i)
+		}
+		if seenOrders[ex.ExerciseOrder] {
+			return fmt.Errorf("exercises[%d]: duplicate exerciseOrder %d", i, ex.ExerciseOrder)
+		}
+		seenOrders[ex.ExerciseOrder] = true
+		exercise, err := service.mustBeVisibleExercise(userId, ex.ExerciseId)
+		if err != nil {
+			return fmt.Errorf("exercises[%d]: %w", i, err)
+		}
+		seen := map[int]bool{}
+		for j := range ex.Sets {
+			set := &ex.Sets[j]
+			if _, err := setInputToKg(unit, set); err != nil {
+				return fmt.Errorf("exercises[%d].sets[%d]: %w", i, j, err)
+			}
+			if err := validateSet(set); err != nil {
+				return fmt.Errorf("exercises[%d].sets[%d]: %w", i, j, err)
+			}
+			if err := validateSetMetrics(exercise.MetricType, *set); err != nil {
+				return fmt.Errorf("exercises[%d].sets[%d]: %w", i, j, err)
+			}
+			if seen[set.SetNumber] {
+				return fmt.Errorf("exercises[%d].sets[%d]: duplicate setNumber %d", i, j, set.SetNumber)
+			}
+			seen[set.SetNumber] = true
+		}
+	}
+	return nil
+}
+
+func normalizeWorkoutTime(w *models.Workout) error {
+	performedAt, offset, err := normalizeTimestamp("performedAt", w.PerformedAt, w.Timezone)
+	if err != nil {
+		return err
 	}
+	w.PerformedAt = performedAt
+	w.UTCOffset = offset
+	return nil
 }
 
-func (service *WorkoutService) CreateWorkout(userId int64, performedAt string, durationMinutes *int, notes *string) (models.Workout, error) {
-	if strings.TrimSpace(performedAt) == "" {
-		return models.Workout{}, fmt.Errorf("performedAt is required")
+func (service *WorkoutService) CreateWorkout(userId int64, details models.WorkoutWithDetails) (models.WorkoutWithDetails, error) {
+	if err := normalizeWorkoutTime(&details.Workout); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	unit, err := service.weightUnit(userId, "")
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := service.validateWorkoutExercises(userId, unit, details.Exercises); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := validateWorkoutGroups(details.Groups, details.Exercises); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	workoutId, err := service.WorkoutRepo.CreateWorkoutWithDetails(userId, details)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	created, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	convertDetails(unit, &created)
+	return created, nil
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (service *WorkoutService) ReplaceWorkoutDetails(userId, workoutId int64, details models.WorkoutWithDetails) (models.WorkoutWithDetails, error) {
+	if err := normalizeWorkoutTime(&details.Workout); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	unit, err := service.weightUnit(userId, "")
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := service.validateWorkoutExercises(userId, unit, details.Exercises); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	current, err := service.WorkoutRepo.ReplaceWorkoutDetails(userId, workoutId, func(current models.WorkoutWithDetails) (models.WorkoutDetailsDiff, error) {
+		return buildDetailsDiff(current, details)
+	})
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := service.RecordService.Recalculate(userId, append(exerciseIdsOf(current.Exercises), exerciseIdsOf(details.Exercises)...)...); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	updated, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
 	}
-	return service.WorkoutRepo.CreateWorkout(userId, performedAt, durationMinutes, notes)
+	convertDetails(unit, &updated)
+	return updated, nil
+}
+
+// buildDetailsDiff works out the writes that turn current into details.
+// Entries with an id must belong to current; without groups, details keeps
+// the current ones.
+func buildDetailsDiff(current, details models.WorkoutWithDetails) (models.WorkoutDetailsDiff, error) {
+	groups := details.Groups
+	if groups == nil {
+		groups = current.Groups
+	}
+	if err := validateWorkoutGroups(groups, details.Exercises); err != nil {
+		return models.WorkoutDetailsDiff{}, err
+	}
+
+	diff := models.WorkoutDetailsDiff{Workout: details.Workout, Groups: details.Groups}
+
+	currentExercises := map[int64]models.WorkoutExerciseWithSets{}
+	for _, ex := range current.Exercises {
+		currentExercises[ex.Id] = ex
+	}
+
+	seenExercises := map[int64]bool{}
+	for i, ex := range details.Exercises {
+		if ex.Id == 0 {
+			diff.InsertWorkoutExercises = append(diff.InsertWorkoutExercises, ex)
+			continue
+		}
+
+		existing, ok := currentExercises[ex.Id]
+		if !ok {
+			return models.WorkoutDetailsDiff{}, fmt.Errorf("exercises[%d]: workout exercise %d does not belong to this workout", i, ex.Id)
+		}
+		if seenExercises[ex.Id] {
+			return models.WorkoutDetailsDiff{}, fmt.Errorf("exercises[%d]: workout exercise %d listed more than once", i, ex.Id)
+		}
+		seenExercises[ex.Id] = true
+
+		if existing.ExerciseId != ex.ExerciseId || existing.ExerciseOrder != ex.ExerciseOrder || !equalPtr(existing.Notes, ex.Notes) || !equalPtr(existing.GroupLabel, ex.GroupLabel) {
+			diff.UpdateWorkoutExercises = append(diff.UpdateWorkoutExercises, ex.WorkoutExercise)
+		}
+
+		currentSets := map[int64]models.Set{}
+		for _, set := range existing.Sets {
+			currentSets[set.Id] = set
+		}
+		seenSets := map[int64]bool{}
+		for j, set := range ex.Sets {
+			set.WorkoutExerciseId = ex.Id
+			if set.Id == 0 {
+				diff.InsertSets = append(diff.InsertSets, set)
+				continue
+			}
+
+			existingSet, ok := currentSets[set.Id]
+			if !ok {
+				return models.WorkoutDetailsDiff{}, fmt.Errorf("exercises[%d].sets[%d]: set %d does not belong to this workout exercise", i, j, set.Id)
+			}
+			if seenSets[set.Id] {
+				return models.WorkoutDetailsDiff{}, fmt.Errorf("exercises[%d].sets[%d]: set %d listed more than once", i, j, set.Id)
+			}
+			seenSets[set.Id] = true
+
+			set.Weight = keepStoredWeight(existingSet.Weight, set.Weight)
+			if setChanged(existingSet, set) {
+				diff.UpdateSets = append(diff.UpdateSets, set)
+			}
+		}
+		for _, set := range existing.Sets {
+			if !seenSets[set.Id] {
+				diff.DeleteSetIds = append(diff.DeleteSetIds, set.Id)
+			}
+		}
+	}
+
+	for _, ex := range current.Exercises {
+		if !seenExercises[ex.Id] {
+			diff.DeleteWorkoutExerciseIds = append(diff.DeleteWorkoutExerciseIds, ex.Id)
+		}
+	}
+	return diff, nil
+}
+
+// abandonStaleSession abandons the user's idle live session, stops its rest
+// timer and drops its sets from the records.
+func (service *WorkoutService) abandonStaleSession(userId int64) error {
+	cutoff := time.Now().UTC().Add(-staleSessionAfter).Format(time.RFC3339)
+	workoutId, err := service.WorkoutRepo.AbandonStale(userId, cutoff)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := service.RestTimerService.CancelForWorkout(userId, workoutId); err != nil {
+		log.Printf("[RestTimer] cancel failed user=%d workout=%d: %v", userId, workoutId, err)
+	}
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// localNow is the current time in the given zone, or UTC when the zone is
+// missing or unknown (normalizeWorkoutTime reports the latter).
+func localNow(timezone *string) string {
+	now := time.Now().UTC()
+	if timezone != nil {
+		if loc, err := time.LoadLocation(*timezone); err == nil {
+			return now.In(loc).Format(time.RFC3339)
+		}
+	}
+	return now.Format(time.RFC3339)
+}
+
+// startSession stores details as the user's live session starting at its
+// performedAt, after abandoning an idle one. It fails with
+// ErrWorkoutInProgress while another session is open.
+func (service *WorkoutService) startSession(userId int64, details models.WorkoutWithDetails) (int64, error) {
+	if err := service.abandonStaleSession(userId); err != nil {
+		return 0, err
+	}
+	if err := normalizeWorkoutTime(&details.Workout); err != nil {
+		return 0, err
+	}
+	details.Status = models.WorkoutInProgress
+	startedAt := details.PerformedAt
+	details.StartedAt = &startedAt
+
+	workoutId, err := service.WorkoutRepo.CreateWorkoutWithDetails(userId, details)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicate) {
+			return 0, ErrWorkoutInProgress
+		}
+		return 0, err
+	}
+	return workoutId, nil
+}
+
+// StartWorkout opens a live session starting now. Only one session per user
+// can be in progress.
+func (service *WorkoutService) StartWorkout(userId int64, timezone, notes *string) (models.WorkoutWithDetails, error) {
+	workoutId, err := service.startSession(userId, models.WorkoutWithDetails{
+		Workout: models.Workout{
+			PerformedAt: localNow(timezone),
+			Timezone:    timezone,
+			Notes:       notes,
+		},
+	})
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	return service.GetWorkoutDetails(userId, workoutId, "", "")
+}
+
+func (service *WorkoutService) GetActiveWorkout(userId int64, unit string) (models.WorkoutWithDetails, error) {
+	if err := service.abandonStaleSession(userId); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	w, err := service.WorkoutRepo.GetInProgress(userId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	return service.GetWorkoutDetails(userId, w.Id, "", unit)
+}
+
+// FinishWorkout completes a live session; its duration is the time from
+// start to now, rounded to whole minutes.
+func (service *WorkoutService) FinishWorkout(userId, workoutId int64) (models.Workout, error) {
+	if err := service.abandonStaleSession(userId); err != nil {
+		return models.Workout{}, err
+	}
+	w, err := service.WorkoutRepo.GetWorkoutById(userId, workoutId)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if w.Status != models.WorkoutInProgress {
+		return models.Workout{}, ErrWorkoutNotInProgress
+	}
+
+	startedAt := w.PerformedAt
+	if w.StartedAt != nil {
+		startedAt = *w.StartedAt
+	}
+	started, err := time.Parse(time.RFC3339, startedAt)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	finished := time.Now().UTC()
+	minutes := max(1, int(math.Round(finished.Sub(started).Minutes())))
+
+	finishedWorkout, err := service.WorkoutRepo.FinishWorkout(userId, workoutId, finished.Format(time.RFC3339), minutes)
+	if errors.Is(err, repo.ErrNotFound) {
+		// finished or abandoned concurrently
+		return models.Workout{}, ErrWorkoutNotInProgress
+	}
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if err := service.RestTimerService.CancelForWorkout(userId, workoutId); err != nil {
+		log.Printf("[RestTimer] cancel failed user=%d workout=%d: %v", userId, workoutId, err)
+	}
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return models.Workout{}, err
+	}
+	return finishedWorkout, nil
 }
 
 func (service *WorkoutService) GetWorkout(userId, workoutId int64) (models.Workout, error) {
 	return service.WorkoutRepo.GetWorkoutById(userId, workoutId)
 }
 
-func (service *WorkoutService) GetWorkoutDetails(userId, workoutId int64) (models.WorkoutWithDetails, error) {
-	return service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+func (service *WorkoutService) GetWorkoutDetails(userId, workoutId int64, formula, unit string) (models.WorkoutWithDetails, error) {
+	formula, err := service.RecordService.ResolveFormula(userId, formula)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	unit, err = service.weightUnit(userId, unit)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+
+	previous, err := service.WorkoutRepo.ListPreviousExercises(userId, details.Workout, exerciseIdsOf(details.Exercises))
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	for _, p := range previous {
+		for j := range p.Sets {
+			convertSet(unit, &p.Sets[j])
+		}
+	}
+	for i := range details.Exercises {
+		ex := &details.Exercises[i]
+		for j := range ex.Sets {
+			set := &ex.Sets[j]
+			set.E1RM = setOneRepMax(formula, set.Reps, effectiveLoad(*set))
+		}
+		if p, ok := previous[ex.ExerciseId]; ok {
+			ex.Previous = &p
+		}
+	}
+	convertDetails(unit, &details)
+	return details, nil
 }
 
-func (service *WorkoutService) ListWorkouts(userId int64, limit, offset int) ([]models.Workout, error) {
-	if limit <= 0 {
-		limit = 25
+func (service *WorkoutService) ListWorkouts(userId int64, filter models.WorkoutFilter) (models.WorkoutList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 25
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	switch filter.Sort {
+	case "":
+		filter.Sort = "date"
+	case "date", "duration", "volume":
+	default:
+		return models.WorkoutList{}, fmt.Errorf("%w: sort must be one of date, duration, volume", ErrInvalidQuery)
+	}
+	if filter.Status != "" && !slices.Contains(models.WorkoutStatuses, filter.Status) {
+		return models.WorkoutList{}, fmt.Errorf("%w: status must be one of %s", ErrInvalidQuery, strings.Join(models.WorkoutStatuses, ", "))
+	}
+	switch filter.Order {
+	case "":
+		filter.Order = "desc"
+	case "asc", "desc":
+	default:
+		return models.WorkoutList{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidQuery)
+	}
+	from, to, err := parseDateFilter(service.RecordService.UserRepo, userId, filter.FromRaw, filter.ToRaw)
+	if err != nil {
+		return models.WorkoutList{}, err
+	}
+	filter.From, filter.To = from, to
+	if filter.MinDuration != nil && filter.MaxDuration != nil && *filter.MinDuration > *filter.MaxDuration {
+		return models.WorkoutList{}, fmt.Errorf("%w: minDuration must not exceed maxDuration", ErrInvalidQuery)
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	sortKey := filter.Sort + ":" + filter.Order
+	after, err := decodeCursor(filter.Cursor, sortKey)
+	if err != nil {
+		return models.WorkoutList{}, err
+	}
+	filter.After = after
+	if after != nil {
+		filter.Offset = 0
+	}
+
+	if err := service.abandonStaleSession(userId); err != nil {
+		return models.WorkoutList{}, err
+	}
+	out, total, next, err := service.WorkoutRepo.ListWorkouts(userId, filter)
+	if err != nil {
+		return models.WorkoutList{}, err
+	}
+	return models.WorkoutList{
+		Workouts:   out,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+		NextCursor: encodeCursor(sortKey, next),
+	}, nil
+}
+
+// maxWeightChangePercent bounds the weight bump of a duplicated workout.
+const maxWeightChangePercent = 100
+
+// DuplicateWorkout copies a workout's groups, exercises and sets into a new
+// workout dated now. By default the copy is a live session whose sets are not
+// ticked off yet, so it fails with ErrWorkoutInProgress while another one is
+// open; opts.Completed logs it as done right away instead. ClearSets copies
+// the exercises only; WeightIncreasePercent scales every weight except the
+// assistance of assisted exercises, rounded to 0.01 in the user's unit.
+func (service *WorkoutService) DuplicateWorkout(userId, workoutId int64, opts models.DuplicateOptions) (models.WorkoutWithDetails, error) {
+	if opts.WeightIncreasePercent <= -maxWeightChangePercent || opts.WeightIncreasePercent > maxWeightChangePercent {
+		return models.WorkoutWithDetails{}, &FieldError{Field: "weightIncreasePercent", Message: fmt.Sprintf("must be above -%d and at most %d", maxWeightChangePercent, maxWeightChangePercent)}
 	}
-	if limit > 200 {
-		limit = 200
+	unit, err := service.weightUnit(userId, "")
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	source, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
 	}
-	if offset < 0 {
-		offset = 0
+
+	details := models.WorkoutWithDetails{
+		Workout: models.Workout{
+			PerformedAt: localNow(opts.Timezone),
+			Timezone:    opts.Timezone,
+			Notes:       source.Notes,
+			RoutineId:   source.RoutineId,
+		},
+		Groups: source.Groups,
+	}
+
+	exercises := map[int64]models.Exercise{}
+	if !opts.ClearSets && opts.WeightIncreasePercent != 0 {
+		exercises, err = service.ExerciseRepo.ListExercisesByIds(exerciseIdsOf(source.Exercises))
+		if err != nil {
+			return models.WorkoutWithDetails{}, err
+		}
 	}
-	return service.WorkoutRepo.ListWorkouts(userId, limit, offset)
+
+	for _, ex := range source.Exercises {
+		copied := models.WorkoutExerciseWithSets{
+			WorkoutExercise: models.WorkoutExercise{
+				ExerciseId:    ex.ExerciseId,
+				ExerciseOrder: ex.ExerciseOrder,
+				Notes:         ex.Notes,
+				GroupLabel:    ex.GroupLabel,
+			},
+		}
+		if !opts.ClearSets {
+			bump := opts.WeightIncreasePercent != 0 && exercises[ex.ExerciseId].LoadType != models.LoadAssisted
+			for _, set := range ex.Sets {
+				copiedSet := set
+				copiedSet.Id = 0
+				copiedSet.WorkoutExerciseId = 0
+				copiedSet.Load = nil
+				copiedSet.CompletedAt = nil
+				if bump && set.Weight != nil {
+					weight := roundTo(fromKg(unit, *set.Weight)*(1+opts.WeightIncreasePercent/100), 2)
+					copiedSet.Weight = toKg(unit, &weight)
+				}
+				copied.Sets = append(copied.Sets, copiedSet)
+			}
+		}
+		details.Exercises = append(details.Exercises, copied)
+	}
+
+	if !opts.Completed {
+		newId, err := service.startSession(userId, details)
+		if err != nil {
+			return models.WorkoutWithDetails{}, err
+		}
+		return service.GetWorkoutDetails(userId, newId, "", unit)
+	}
+
+	if err := normalizeWorkoutTime(&details.Workout); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	newId, err := service.WorkoutRepo.CreateWorkoutWithDetails(userId, details)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	return service.GetWorkoutDetails(userId, newId, "", unit)
+}
+
+// RepeatRoutine duplicates the user's last completed workout of a routine.
+func (service *WorkoutService) RepeatRoutine(userId, routineId int64, opts models.DuplicateOptions) (models.WorkoutWithDetails, error) {
+	last, err := service.WorkoutRepo.GetLatestByRoutine(userId, routineId)
+	if err != nil {
+		return models.WorkoutWithDetails{}, err
+	}
+	return service.DuplicateWorkout(userId, last.Id, opts)
 }
 
-func (service *WorkoutService) UpdateWorkout(userId, workoutId int64, performedAt string, durationMinutes *int, notes *string) (models.Workout, error) {
-	if strings.TrimSpace(performedAt) == "" {
-		return models.Workout{}, fmt.Errorf("performedAt is required")
+func (service *WorkoutService) UpdateWorkout(userId, workoutId int64, workout models.Workout) (models.Workout, error) {
+	if err := normalizeWorkoutTime(&workout); err != nil {
+		return models.Workout{}, err
+	}
+	updated, err := service.WorkoutRepo.UpdateWorkout(userId, workoutId, workout)
+	if err != nil {
+		return models.Workout{}, err
 	}
-	return service.WorkoutRepo.UpdateWorkout(userId, workoutId, performedAt, durationMinutes, notes)
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.Workout{}, err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return models.Workout{}, err
+	}
+	return updated, nil
 }
 
 func (service *WorkoutService) DeleteWorkout(userId, workoutId int64) error {
-	return service.WorkoutRepo.DeleteWorkout(userId, workoutId)
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return err
+	}
+	if err := service.RestTimerService.CancelForWorkout(userId, workoutId); err != nil {
+		log.Printf("[RestTimer] cancel failed user=%d workout=%d: %v", userId, workoutId, err)
+	}
+	if err := service.WorkoutRepo.DeleteWorkout(userId, workoutId); err != nil {
+		return err
+	}
+	if err := service.RecordService.Recalculate(userId, exerciseIdsOf(details.Exercises)...); err != nil {
+		return err
+	}
+	return nil
 }
 
-func (service *WorkoutService) AddExerciseToWorkout(userId, workoutId, exerciseId int64, exerciseOrder int, notes *string) (models.WorkoutExercise, error) {
+// groupIdForLabel resolves a member's group label within its own workout, so
+// exercises can't join a group from another workout.
+func (service *WorkoutService) groupIdForLabel(workoutId int64, label *string) (*int64, error) {
+	label = normalizeGroupLabel(label)
+	if label == nil {
+		return nil, nil
+	}
+	group, err := service.WorkoutGroupRepo.GetByLabel(workoutId, *label)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, fmt.Errorf("groupLabel %q is not a group of this workout", *label)
+		}
+		return nil, err
+	}
+	return &group.Id, nil
+}
+
+func (service *WorkoutService) AddExerciseToWorkout(userId, workoutId, exerciseId int64, exerciseOrder int, notes, groupLabel *string) (models.WorkoutExercise, error) {
 	if err := service.WorkoutRepo.MustBeWorkoutOwner(userId, workoutId); err != nil {
 		return models.WorkoutExercise{}, err
 	}
+	if exerciseOrder == 0 {
+		next, err := service.WorkoutExerciseRepo.NextExerciseOrder(workoutId)
+		if err != nil {
+			return models.WorkoutExercise{}, err
+		}
+		exerciseOrder = next
+	}
 	if exerciseOrder <= 0 {
 		return models.WorkoutExercise{}, fmt.Errorf("exerciseOrder must be >= 1")
 	}
-	return service.WorkoutExerciseRepo.AddExercise(workoutId, exerciseId, exerciseOrder, notes)
+	if _, err := service.mustBeVisibleExercise(userId, exerciseId); err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	groupId, err := service.groupIdForLabel(workoutId, groupLabel)
+	if err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	we, err := service.WorkoutExerciseRepo.AddExercise(workoutId, exerciseId, exerciseOrder, notes, groupId)
+	if errors.Is(err, repo.ErrDuplicate) {
+		return models.WorkoutExercise{}, fmt.Errorf("exerciseOrder %d is already taken in this workout", exerciseOrder)
+	}
+	return we, err
 }
 
-func (service *WorkoutService) UpdateWorkoutExercise(userId int64, workoutExerciseId int64, exerciseOrder int, notes *string) (models.WorkoutExercise, error) {
+func (service *WorkoutService) UpdateWorkoutExercise(userId int64, workoutExerciseId int64, exerciseOrder int, notes, groupLabel *string) (models.WorkoutExercise, error) {
 	we, err := service.WorkoutExerciseRepo.GetById(workoutExerciseId)
 	if err != nil {
 		return models.WorkoutExercise{}, err
@@ -83,10 +767,54 @@ func (service *WorkoutService) UpdateWorkoutExercise(userId int64, workoutExerci
 		return models.WorkoutExercise{}, err
 	}
 
+	if exerciseOrder == 0 {
+		exerciseOrder = we.ExerciseOrder
+	}
 	if exerciseOrder <= 0 {
 		return models.WorkoutExercise{}, fmt.Errorf("exerciseOrder must be >= 1")
 	}
-	return service.WorkoutExerciseRepo.Update(workoutExerciseId, exerciseOrder, notes)
+	groupId, err := service.groupIdForLabel(we.WorkoutId, groupLabel)
+	if err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	updated, err := service.WorkoutExerciseRepo.Update(workoutExerciseId, exerciseOrder, notes, groupId)
+	if errors.Is(err, repo.ErrDuplicate) {
+		return models.WorkoutExercise{}, fmt.Errorf("exerciseOrder %d is already taken in this workout", exerciseOrder)
+	}
+	return updated, err
+}
+
+// ReorderWorkoutExercises renumbers the workout's exercises in the given
+// order; the list must name every exercise of the workout exactly once.
+func (service *WorkoutService) ReorderWorkoutExercises(userId, workoutId int64, workoutExerciseIds []int64) ([]models.WorkoutExercise, error) {
+	if err := service.WorkoutRepo.MustBeWorkoutOwner(userId, workoutId); err != nil {
+		return nil, err
+	}
+
+	err := service.WorkoutExerciseRepo.Reorder(workoutId, workoutExerciseIds, func(current []int64) error {
+		belongs := map[int64]bool{}
+		for _, id := range current {
+			belongs[id] = true
+		}
+		seen := map[int64]bool{}
+		for i, id := range workoutExerciseIds {
+			if !belongs[id] {
+				return fmt.Errorf("workoutExerciseIds[%d]: workout exercise %d does not belong to this workout", i, id)
+			}
+			if seen[id] {
+				return fmt.Errorf("workoutExerciseIds[%d]: workout exercise %d listed more than once", i, id)
+			}
+			seen[id] = true
+		}
+		if len(seen) != len(current) {
+			return fmt.Errorf("workoutExerciseIds must list all %d exercises of the workout", len(current))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.WorkoutExerciseRepo.ListByWorkout(workoutId)
 }
 
 func (service *WorkoutService) DeleteWorkoutExercise(userId int64, workoutExerciseId int64) error {
@@ -99,10 +827,65 @@ func (service *WorkoutService) DeleteWorkoutExercise(userId int64, workoutExerci
 		return err
 	}
 
-	return service.WorkoutExerciseRepo.Delete(workoutExerciseId)
+	if err := service.WorkoutExerciseRepo.Delete(workoutExerciseId); err != nil {
+		return err
+	}
+	if err := service.RecordService.Recalculate(userId, we.ExerciseId); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (service *WorkoutService) CreateWorkoutGroup(userId, workoutId int64, group models.ExerciseGroup) (models.ExerciseGroup, error) {
+	if err := service.WorkoutRepo.MustBeWorkoutOwner(userId, workoutId); err != nil {
+		return models.ExerciseGroup{}, err
+	}
+	if strings.TrimSpace(group.Label) == "" {
+		existing, err := service.WorkoutGroupRepo.ListByWorkout(workoutId)
+		if err != nil {
+			return models.ExerciseGroup{}, err
+		}
+		group.Label = nextGroupLabel(existing)
+	}
+	if err := validateGroup(&group); err != nil {
+		return models.ExerciseGroup{}, err
+	}
+	return service.WorkoutGroupRepo.Create(workoutId, group)
+}
+
+func (service *WorkoutService) UpdateWorkoutGroup(userId, groupId int64, group models.ExerciseGroup) (models.ExerciseGroup, error) {
+	current, workoutId, err := service.WorkoutGroupRepo.GetById(groupId)
+	if err != nil {
+		return models.ExerciseGroup{}, err
+	}
+	if err := service.WorkoutRepo.MustBeWorkoutOwner(userId, workoutId); err != nil {
+		return models.ExerciseGroup{}, err
+	}
+
+	if strings.TrimSpace(group.Label) == "" {
+		group.Label = current.Label
+	}
+	if strings.TrimSpace(group.GroupType) == "" {
+		group.GroupType = current.GroupType
+	}
+	if err := validateGroup(&group); err != nil {
+		return models.ExerciseGroup{}, err
+	}
+	return service.WorkoutGroupRepo.Update(groupId, group)
+}
+
+func (service *WorkoutService) DeleteWorkoutGroup(userId, groupId int64) error {
+	_, workoutId, err := service.WorkoutGroupRepo.GetById(groupId)
+	if err != nil {
+		return err
+	}
+	if err := service.WorkoutRepo.MustBeWorkoutOwner(userId, workoutId); err != nil {
+		return err
+	}
+	return service.WorkoutGroupRepo.Delete(groupId)
 }
 
-func (service *WorkoutService) AddSet(userId int64, workoutExerciseId int64, setNumber int, reps *int, weight *float64) (models.Set, error) {
+func (service *WorkoutService) AddSet(userId int64, workoutExerciseId int64, set models.Set) (models.Set, error) {
 	we, err := service.WorkoutExerciseRepo.GetById(workoutExerciseId)
 	if err != nil {
 		return models.Set{}, err
@@ -112,14 +895,30 @@ func (service *WorkoutService) AddSet(userId int64, workoutExerciseId int64, set
 		return models.Set{}, err
 	}
 
-	if setNumber <= 0 {
-		return models.Set{}, fmt.Errorf("setNumber must be >= 1")
+	if set.SetNumber == 0 {
+		if set.SetNumber, err = service.SetRepo.NextSetNumber(workoutExerciseId); err != nil {
+			return models.Set{}, err
+		}
+	}
+	unit, err := service.prepareSet(userId, we.ExerciseId, &set)
+	if err != nil {
+		return models.Set{}, err
 	}
 
-	return service.SetRepo.Create(workoutExerciseId, setNumber, reps, weight)
+	set, err = service.SetRepo.Create(workoutExerciseId, set)
+	if err != nil {
+		return models.Set{}, err
+	}
+	if err := service.recordsForSet(userId, we, &set); err != nil {
+		return models.Set{}, err
+	}
+	convertSet(unit, &set)
+	return set, nil
 }
 
-func (service *WorkoutService) UpdateSet(userId int64, setId int64, reps *int, weight *float64) (models.Set, error) {
+// UpdateSet replaces the set's values. A non-nil completed also ticks the set
+// off (or clears it) like CompleteSet does.
+func (service *WorkoutService) UpdateSet(userId int64, setId int64, input models.Set, completed *bool) (models.Set, error) {
 	set, err := service.SetRepo.GetById(setId)
 	if err != nil {
 		return models.Set{}, err
@@ -134,7 +933,110 @@ func (service *WorkoutService) UpdateSet(userId int64, setId int64, reps *int, w
 		return models.Set{}, err
 	}
 
-	return service.SetRepo.Update(setId, reps, weight)
+	input.Id = set.Id
+	input.WorkoutExerciseId = set.WorkoutExerciseId
+	input.SetNumber = set.SetNumber
+	if input.SetType == "" {
+		input.SetType = set.SetType
+	}
+	stored := set
+	set = input
+	unit, err := service.prepareSet(userId, we.ExerciseId, &set)
+	if err != nil {
+		return models.Set{}, err
+	}
+	set.Weight = keepStoredWeight(stored.Weight, set.Weight)
+
+	if completed != nil {
+		if err := service.mustBeInProgress(userId, we.WorkoutId); err != nil {
+			return models.Set{}, err
+		}
+	}
+
+	updated, err := service.SetRepo.Update(setId, set)
+	if err != nil {
+		return models.Set{}, err
+	}
+	if completed != nil {
+		if updated, err = service.markCompleted(userId, we, updated, *completed); err != nil {
+			return models.Set{}, err
+		}
+	}
+	if err := service.recordsForSet(userId, we, &updated); err != nil {
+		return models.Set{}, err
+	}
+	convertSet(unit, &updated)
+	return updated, nil
+}
+
+func (service *WorkoutService) mustBeInProgress(userId, workoutId int64) error {
+	if err := service.abandonStaleSession(userId); err != nil {
+		return err
+	}
+	w, err := service.WorkoutRepo.GetWorkoutById(userId, workoutId)
+	if err != nil {
+		return err
+	}
+	if w.Status != models.WorkoutInProgress {
+		return ErrWorkoutNotInProgress
+	}
+	return nil
+}
+
+// markCompleted sets or clears the completion time of a set of a live
+// session. Ticking a set off starts the rest after it; ticking an already
+// completed set keeps its original time and leaves the timer alone.
+func (service *WorkoutService) markCompleted(userId int64, we models.WorkoutExercise, set models.Set, completed bool) (models.Set, error) {
+	if !completed {
+		if err := service.RestTimerService.CancelForSet(userId, set.Id); err != nil {
+			log.Printf("[RestTimer] cancel failed user=%d set=%d: %v", userId, set.Id, err)
+		}
+		return service.SetRepo.SetCompletedAt(set.Id, nil)
+	}
+	if set.CompletedAt != nil {
+		return set, nil
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	updated, err := service.SetRepo.SetCompletedAt(set.Id, &now)
+	if err != nil {
+		return models.Set{}, err
+	}
+	timer, err := service.RestTimerService.StartAfterSet(userId, we.WorkoutId, we.ExerciseId, updated)
+	if err != nil {
+		log.Printf("[RestTimer] start failed user=%d set=%d: %v", userId, set.Id, err)
+	}
+	updated.RestTimer = timer
+	return updated, nil
+}
+
+// CompleteSet ticks a set of a live session off (or back on).
+func (service *WorkoutService) CompleteSet(userId, setId int64, completed bool) (models.Set, error) {
+	set, err := service.SetRepo.GetById(setId)
+	if err != nil {
+		return models.Set{}, err
+	}
+	we, err := service.WorkoutExerciseRepo.GetById(set.WorkoutExerciseId)
+	if err != nil {
+		return models.Set{}, err
+	}
+	if err := service.mustBeInProgress(userId, we.WorkoutId); err != nil {
+		return models.Set{}, err
+	}
+
+	updated, err := service.markCompleted(userId, we, set, completed)
+	if err != nil {
+		return models.Set{}, err
+	}
+	if err := service.recordsForSet(userId, we, &updated); err != nil {
+		return models.Set{}, err
+	}
+	unit, err := service.weightUnit(userId, "")
+	if err != nil {
+		return models.Set{}, err
+	}
+	convertSet(unit, &updated)
+	return updated, nil
 }
 
 func (service *WorkoutService) DeleteSet(userId int64, setId int64) error {
@@ -152,9 +1054,103 @@ func (service *WorkoutService) DeleteSet(userId int64, setId int64) error {
 		return err
 	}
 
-	return service.SetRepo.Delete(setId)
+	if err := service.RestTimerService.CancelForSet(userId, setId); err != nil {
+		log.Printf("[RestTimer] cancel failed user=%d set=%d: %v", userId, setId, err)
+	}
+	if err := service.SetRepo.Delete(setId); err != nil {
+		return err
+	}
+	if err := service.RecordService.Recalculate(userId, we.ExerciseId); err != nil {
+		return err
+	}
+	return nil
 }
 
-func (service *WorkoutService) GetWorkoutReport(userId, workoutId int64) (models.WorkoutReport, error) {
-	return service.WorkoutRepo.GetWorkoutReport(userId, workoutId)
+func (service *WorkoutService) GetWorkoutReport(userId, workoutId int64, formula, unit string, excludeWarmups bool) (models.WorkoutReport, error) {
+	formula, err := service.RecordService.ResolveFormula(userId, formula)
+	if err != nil {
+		return models.WorkoutReport{}, err
+	}
+	unit, err = service.weightUnit(userId, unit)
+	if err != nil {
+		return models.WorkoutReport{}, err
+	}
+
+	report, err := service.WorkoutRepo.GetWorkoutReport(userId, workoutId, excludeWarmups)
+	if err != nil {
+		return models.WorkoutReport{}, err
+	}
+	report.E1RMFormula = formula
+	report.WeightUnit = unit
+
+	details, err := service.WorkoutRepo.GetWorkoutDetails(userId, workoutId)
+	if err != nil {
+		return models.WorkoutReport{}, err
+	}
+	best := map[int64]float64{}
+	groups := map[string]*models.WorkoutReportGroup{}
+	report.Groups = []models.WorkoutReportGroup{}
+	for _, g := range details.Groups {
+		report.Groups = append(report.Groups, models.WorkoutReportGroup{
+			Label:       g.Label,
+			GroupType:   g.GroupType,
+			Rounds:      g.Rounds,
+			RestSeconds: g.RestSeconds,
+			ExerciseIds: []int64{},
+		})
+	}
+	for i := range report.Groups {
+		groups[report.Groups[i].Label] = &report.Groups[i]
+	}
+	for _, we := range details.Exercises {
+		var group *models.WorkoutReportGroup
+		if we.GroupLabel != nil {
+			group = groups[*we.GroupLabel]
+		}
+		sets := 0
+		for _, set := range we.Sets {
+			if excludeWarmups && set.SetType == models.SetTypeWarmup {
+				continue
+			}
+			sets++
+			if group != nil && set.Reps != nil {
+				if load := effectiveLoad(set); load != nil {
+					group.TotalVolume += float64(*set.Reps) * *load
+				}
+			}
+			if e1rm := setOneRepMax(formula, set.Reps, effectiveLoad(set)); e1rm != nil && *e1rm > best[we.ExerciseId] {
+				best[we.ExerciseId] = *e1rm
+			}
+		}
+		if group != nil {
+			// a round is one pass through every member, so the member with
+			// the fewest sets bounds the rounds completed
+			if len(group.ExerciseIds) == 0 || sets < group.CompletedRounds {
+				group.CompletedRounds = sets
+			}
+			group.ExerciseIds = append(group.ExerciseIds, we.ExerciseId)
+			group.TotalSets += sets
+		}
+	}
+	for i := range report.Groups {
+		report.Groups[i].TotalVolume = fromKg(unit, report.Groups[i].TotalVolume)
+	}
+	for i := range report.Exercises {
+		ex := &report.Exercises[i]
+		if e1rm, ok := best[ex.ExerciseId]; ok {
+			ex.BestE1RM = fromKgPtr(unit, &e1rm)
+		}
+		ex.MaxWeight = fromKgPtr(unit, ex.MaxWeight)
+		ex.TotalVolume = fromKg(unit, ex.TotalVolume)
+	}
+	report.TotalVolume = fromKg(unit, report.TotalVolume)
+	report.Bodyweight = fromKgPtr(unit, report.Bodyweight)
+
+	records, err := service.RecordService.ListWorkoutRecords(userId, workoutId)
+	if err != nil {
+		return models.WorkoutReport{}, err
+	}
+	convertRecords(unit, records)
+	report.PersonalRecords = records
+	return report, nil
 }
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"workout-tracker/internal/models"
+)
+
+func TestAssignMissingNumbers(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []int
+		want []int
+	}{
+		{"empty", []int{}, []int{}},
+		{"all missing", []int{0, 0, 0}, []int{1, 2, 3}},
+		{"after the entry before", []int{0, 3, 0}, []int{1, 3, 4}},
+		{"skips used numbers", []int{0, 1}, []int{2, 1}},
+		{"out of order", []int{2, 0, 1}, []int{2, 3, 1}},
+		{"all given", []int{5, 2}, []int{5, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Clone(tt.in)
+			numbers := make([]*int, 0, len(got))
+			for i := range got {
+				numbers = append(numbers, &got[i])
+			}
+			assignMissingNumbers(numbers)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("assignMissingNumbers(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func detailsSet(id int64, number, reps int, weight float64) models.Set {
+	return models.Set{Id: id, SetNumber: number, SetType: models.SetTypeNormal, Reps: &reps, Weight: &weight}
+}
+
+func currentDetails() models.WorkoutWithDetails {
+	return models.WorkoutWithDetails{
+		Groups: []models.ExerciseGroup{{Id: 1, Label: "A", GroupType: models.GroupSuperset}},
+		Exercises: []models.WorkoutExerciseWithSets{
+			{
+				WorkoutExercise: models.WorkoutExercise{Id: 1, ExerciseId: 10, ExerciseOrder: 1, GroupLabel: ptr("A")},
+				Sets:            []models.Set{detailsSet(11, 1, 5, 100), detailsSet(12, 2, 5, 100)},
+			},
+			{
+				WorkoutExercise: models.WorkoutExercise{Id: 2, ExerciseId: 20, ExerciseOrder: 2},
+				Sets:            []models.Set{detailsSet(21, 1, 8, 40)},
+			},
+		},
+	}
+}
+
+func ids[T any](items []T, id func(T) int64) []int64 {
+	out := []int64{}
+	for _, item := range items {
+		out = append(out, id(item))
+	}
+	return out
+}
+
+func TestBuildDetailsDiff(t *testing.T) {
+	setId := func(s models.Set) int64 { return s.Id }
+	exerciseId := func(we models.WorkoutExercise) int64 { return we.Id }
+
+	t.Run("unchanged document", func(t *testing.T) {
+		diff, err := buildDetailsDiff(currentDetails(), currentDetails())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(diff.UpdateSets)+len(diff.InsertSets)+len(diff.DeleteSetIds)+len(diff.UpdateWorkoutExercises)+len(diff.InsertWorkoutExercises)+len(diff.DeleteWorkoutExerciseIds) != 0 {
+			t.Errorf("expected no changes, got %+v", diff)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		details := currentDetails()
+		details.Groups = []models.ExerciseGroup{}
+		first := &details.Exercises[0]
+		first.GroupLabel = nil
+		// a weight sent back through a rounded lb round trip is not a change
+		first.Sets[0].Weight = toKg(models.UnitLb, ptr(220.46))
+		first.Sets[1].Reps = ptr(6)
+		first.Sets = append(first.Sets, detailsSet(0, 3, 4, 100))
+		details.Exercises = append(details.Exercises[:1], models.WorkoutExerciseWithSets{
+			WorkoutExercise: models.WorkoutExercise{ExerciseId: 30, ExerciseOrder: 2},
+		})
+
+		diff, err := buildDetailsDiff(currentDetails(), details)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(diff.UpdateSets, setId); !slices.Equal(got, []int64{12}) {
+			t.Errorf("UpdateSets = %v, want [12]", got)
+		}
+		if len(diff.InsertSets) != 1 || diff.InsertSets[0].WorkoutExerciseId != 1 {
+			t.Errorf("InsertSets = %+v, want one set on workout exercise 1", diff.InsertSets)
+		}
+		if len(diff.DeleteSetIds) != 0 {
+			t.Errorf("DeleteSetIds = %v, want none; the sets of removed exercises go with them", diff.DeleteSetIds)
+		}
+		if got := ids(diff.UpdateWorkoutExercises, exerciseId); !slices.Equal(got, []int64{1}) {
+			t.Errorf("UpdateWorkoutExercises = %v, want [1]", got)
+		}
+		if len(diff.InsertWorkoutExercises) != 1 {
+			t.Errorf("InsertWorkoutExercises = %+v, want one", diff.InsertWorkoutExercises)
+		}
+		if !slices.Equal(diff.DeleteWorkoutExerciseIds, []int64{2}) {
+			t.Errorf("DeleteWorkoutExerciseIds = %v, want [2]", diff.DeleteWorkoutExerciseIds)
+		}
+		if diff.Groups == nil || len(diff.Groups) != 0 {
+			t.Errorf("Groups = %v, want an empty list that removes them", diff.Groups)
+		}
+	})
+
+	t.Run("omitted groups are kept", func(t *testing.T) {
+		details := currentDetails()
+		details.Groups = nil
+		diff, err := buildDetailsDiff(currentDetails(), details)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff.Groups != nil {
+			t.Errorf("Groups = %v, want nil", diff.Groups)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		modify func(d *models.WorkoutWithDetails)
+	}{
+		{"exercise of another workout", func(d *models.WorkoutWithDetails) { d.Exercises[1].Id = 99 }},
+		{"exercise listed twice", func(d *models.WorkoutWithDetails) { d.Exercises[1].Id = 1 }},
+		{"set of another exercise", func(d *models.WorkoutWithDetails) { d.Exercises[0].Sets[0].Id = 21 }},
+		{"set listed twice", func(d *models.WorkoutWithDetails) { d.Exercises[0].Sets[1].Id = 11 }},
+		{"label not among the kept groups", func(d *models.WorkoutWithDetails) {
+			d.Groups = nil
+			d.Exercises[1].GroupLabel = ptr("B")
+		}},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			details := currentDetails()
+			tt.modify(&details)
+			if _, err := buildDetailsDiff(currentDetails(), details); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}